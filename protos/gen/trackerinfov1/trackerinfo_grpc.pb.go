@@ -19,9 +19,15 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	TrackerInfo_Sources_FullMethodName     = "/trackerinfo.TrackerInfo/Sources"
-	TrackerInfo_IdsBySource_FullMethodName = "/trackerinfo.TrackerInfo/IdsBySource"
-	TrackerInfo_List_FullMethodName        = "/trackerinfo.TrackerInfo/List"
+	TrackerInfo_Sources_FullMethodName        = "/trackerinfo.TrackerInfo/Sources"
+	TrackerInfo_IdsBySource_FullMethodName    = "/trackerinfo.TrackerInfo/IdsBySource"
+	TrackerInfo_List_FullMethodName           = "/trackerinfo.TrackerInfo/List"
+	TrackerInfo_Readings_FullMethodName       = "/trackerinfo.TrackerInfo/Readings"
+	TrackerInfo_Watch_FullMethodName          = "/trackerinfo.TrackerInfo/Watch"
+	TrackerInfo_Nearest_FullMethodName        = "/trackerinfo.TrackerInfo/Nearest"
+	TrackerInfo_ListInBounds_FullMethodName   = "/trackerinfo.TrackerInfo/ListInBounds"
+	TrackerInfo_TrackerHistory_FullMethodName = "/trackerinfo.TrackerInfo/TrackerHistory"
+	TrackerInfo_SourceStatus_FullMethodName   = "/trackerinfo.TrackerInfo/SourceStatus"
 )
 
 // TrackerInfoClient is the client API for TrackerInfo service.
@@ -31,6 +37,13 @@ type TrackerInfoClient interface {
 	Sources(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*SourcesResponse, error)
 	IdsBySource(ctx context.Context, in *SourceRequest, opts ...grpc.CallOption) (*IdsBySourceResponse, error)
 	List(ctx context.Context, in *ModifiedFromRequest, opts ...grpc.CallOption) (*FullInfoResponse, error)
+	Readings(ctx context.Context, in *ReadingsRequest, opts ...grpc.CallOption) (*ReadingsResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (TrackerInfo_WatchClient, error)
+	Nearest(ctx context.Context, in *NearestRequest, opts ...grpc.CallOption) (*NearestResponse, error)
+	ListInBounds(ctx context.Context, in *BoundsRequest, opts ...grpc.CallOption) (*FullInfoResponse, error)
+	TrackerHistory(ctx context.Context, in *TrackerRequest, opts ...grpc.CallOption) (*TrackerHistoryResponse, error)
+	// health of the source or of all sources if the source is empty
+	SourceStatus(ctx context.Context, in *SourceRequest, opts ...grpc.CallOption) (*SourceStatusResponse, error)
 }
 
 type trackerInfoClient struct {
@@ -68,6 +81,83 @@ func (c *trackerInfoClient) List(ctx context.Context, in *ModifiedFromRequest, o
 	return out, nil
 }
 
+func (c *trackerInfoClient) Readings(ctx context.Context, in *ReadingsRequest, opts ...grpc.CallOption) (*ReadingsResponse, error) {
+	out := new(ReadingsResponse)
+	err := c.cc.Invoke(ctx, TrackerInfo_Readings_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerInfoClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (TrackerInfo_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TrackerInfo_ServiceDesc.Streams[0], TrackerInfo_Watch_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trackerInfoWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TrackerInfo_WatchClient interface {
+	Recv() (*WatchEvent, error)
+	grpc.ClientStream
+}
+
+type trackerInfoWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *trackerInfoWatchClient) Recv() (*WatchEvent, error) {
+	m := new(WatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *trackerInfoClient) Nearest(ctx context.Context, in *NearestRequest, opts ...grpc.CallOption) (*NearestResponse, error) {
+	out := new(NearestResponse)
+	err := c.cc.Invoke(ctx, TrackerInfo_Nearest_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerInfoClient) ListInBounds(ctx context.Context, in *BoundsRequest, opts ...grpc.CallOption) (*FullInfoResponse, error) {
+	out := new(FullInfoResponse)
+	err := c.cc.Invoke(ctx, TrackerInfo_ListInBounds_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerInfoClient) TrackerHistory(ctx context.Context, in *TrackerRequest, opts ...grpc.CallOption) (*TrackerHistoryResponse, error) {
+	out := new(TrackerHistoryResponse)
+	err := c.cc.Invoke(ctx, TrackerInfo_TrackerHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerInfoClient) SourceStatus(ctx context.Context, in *SourceRequest, opts ...grpc.CallOption) (*SourceStatusResponse, error) {
+	out := new(SourceStatusResponse)
+	err := c.cc.Invoke(ctx, TrackerInfo_SourceStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TrackerInfoServer is the server API for TrackerInfo service.
 // All implementations must embed UnimplementedTrackerInfoServer
 // for forward compatibility
@@ -75,6 +165,13 @@ type TrackerInfoServer interface {
 	Sources(context.Context, *EmptyRequest) (*SourcesResponse, error)
 	IdsBySource(context.Context, *SourceRequest) (*IdsBySourceResponse, error)
 	List(context.Context, *ModifiedFromRequest) (*FullInfoResponse, error)
+	Readings(context.Context, *ReadingsRequest) (*ReadingsResponse, error)
+	Watch(*WatchRequest, TrackerInfo_WatchServer) error
+	Nearest(context.Context, *NearestRequest) (*NearestResponse, error)
+	ListInBounds(context.Context, *BoundsRequest) (*FullInfoResponse, error)
+	TrackerHistory(context.Context, *TrackerRequest) (*TrackerHistoryResponse, error)
+	// health of the source or of all sources if the source is empty
+	SourceStatus(context.Context, *SourceRequest) (*SourceStatusResponse, error)
 	mustEmbedUnimplementedTrackerInfoServer()
 }
 
@@ -91,6 +188,24 @@ func (UnimplementedTrackerInfoServer) IdsBySource(context.Context, *SourceReques
 func (UnimplementedTrackerInfoServer) List(context.Context, *ModifiedFromRequest) (*FullInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
 }
+func (UnimplementedTrackerInfoServer) Readings(context.Context, *ReadingsRequest) (*ReadingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Readings not implemented")
+}
+func (UnimplementedTrackerInfoServer) Watch(*WatchRequest, TrackerInfo_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedTrackerInfoServer) Nearest(context.Context, *NearestRequest) (*NearestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Nearest not implemented")
+}
+func (UnimplementedTrackerInfoServer) ListInBounds(context.Context, *BoundsRequest) (*FullInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInBounds not implemented")
+}
+func (UnimplementedTrackerInfoServer) TrackerHistory(context.Context, *TrackerRequest) (*TrackerHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TrackerHistory not implemented")
+}
+func (UnimplementedTrackerInfoServer) SourceStatus(context.Context, *SourceRequest) (*SourceStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SourceStatus not implemented")
+}
 func (UnimplementedTrackerInfoServer) mustEmbedUnimplementedTrackerInfoServer() {}
 
 // UnsafeTrackerInfoServer may be embedded to opt out of forward compatibility for this service.
@@ -158,6 +273,117 @@ func _TrackerInfo_List_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrackerInfo_Readings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerInfoServer).Readings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerInfo_Readings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerInfoServer).Readings(ctx, req.(*ReadingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerInfo_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrackerInfoServer).Watch(m, &trackerInfoWatchServer{stream})
+}
+
+type TrackerInfo_WatchServer interface {
+	Send(*WatchEvent) error
+	grpc.ServerStream
+}
+
+type trackerInfoWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *trackerInfoWatchServer) Send(m *WatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TrackerInfo_Nearest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NearestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerInfoServer).Nearest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerInfo_Nearest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerInfoServer).Nearest(ctx, req.(*NearestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerInfo_ListInBounds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BoundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerInfoServer).ListInBounds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerInfo_ListInBounds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerInfoServer).ListInBounds(ctx, req.(*BoundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerInfo_TrackerHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrackerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerInfoServer).TrackerHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerInfo_TrackerHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerInfoServer).TrackerHistory(ctx, req.(*TrackerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerInfo_SourceStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerInfoServer).SourceStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerInfo_SourceStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerInfoServer).SourceStatus(ctx, req.(*SourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TrackerInfo_ServiceDesc is the grpc.ServiceDesc for TrackerInfo service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -177,6 +403,198 @@ var TrackerInfo_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "List",
 			Handler:    _TrackerInfo_List_Handler,
 		},
+		{
+			MethodName: "Readings",
+			Handler:    _TrackerInfo_Readings_Handler,
+		},
+		{
+			MethodName: "Nearest",
+			Handler:    _TrackerInfo_Nearest_Handler,
+		},
+		{
+			MethodName: "ListInBounds",
+			Handler:    _TrackerInfo_ListInBounds_Handler,
+		},
+		{
+			MethodName: "TrackerHistory",
+			Handler:    _TrackerInfo_TrackerHistory_Handler,
+		},
+		{
+			MethodName: "SourceStatus",
+			Handler:    _TrackerInfo_SourceStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _TrackerInfo_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "trackerinfo.proto",
+}
+
+const (
+	TrackerInfoAdmin_PendingRefreshes_FullMethodName = "/trackerinfo.TrackerInfoAdmin/PendingRefreshes"
+	TrackerInfoAdmin_ConfirmRefresh_FullMethodName   = "/trackerinfo.TrackerInfoAdmin/ConfirmRefresh"
+	TrackerInfoAdmin_DiscardRefresh_FullMethodName   = "/trackerinfo.TrackerInfoAdmin/DiscardRefresh"
+)
+
+// TrackerInfoAdminClient is the client API for TrackerInfoAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TrackerInfoAdminClient interface {
+	PendingRefreshes(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*PendingRefreshesResponse, error)
+	// applies the refused refresh of the source
+	ConfirmRefresh(ctx context.Context, in *SourceRequest, opts ...grpc.CallOption) (*EmptyResponse, error)
+	DiscardRefresh(ctx context.Context, in *SourceRequest, opts ...grpc.CallOption) (*EmptyResponse, error)
+}
+
+type trackerInfoAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTrackerInfoAdminClient(cc grpc.ClientConnInterface) TrackerInfoAdminClient {
+	return &trackerInfoAdminClient{cc}
+}
+
+func (c *trackerInfoAdminClient) PendingRefreshes(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*PendingRefreshesResponse, error) {
+	out := new(PendingRefreshesResponse)
+	err := c.cc.Invoke(ctx, TrackerInfoAdmin_PendingRefreshes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerInfoAdminClient) ConfirmRefresh(ctx context.Context, in *SourceRequest, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, TrackerInfoAdmin_ConfirmRefresh_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerInfoAdminClient) DiscardRefresh(ctx context.Context, in *SourceRequest, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, TrackerInfoAdmin_DiscardRefresh_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TrackerInfoAdminServer is the server API for TrackerInfoAdmin service.
+// All implementations must embed UnimplementedTrackerInfoAdminServer
+// for forward compatibility
+type TrackerInfoAdminServer interface {
+	PendingRefreshes(context.Context, *EmptyRequest) (*PendingRefreshesResponse, error)
+	// applies the refused refresh of the source
+	ConfirmRefresh(context.Context, *SourceRequest) (*EmptyResponse, error)
+	DiscardRefresh(context.Context, *SourceRequest) (*EmptyResponse, error)
+	mustEmbedUnimplementedTrackerInfoAdminServer()
+}
+
+// UnimplementedTrackerInfoAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedTrackerInfoAdminServer struct {
+}
+
+func (UnimplementedTrackerInfoAdminServer) PendingRefreshes(context.Context, *EmptyRequest) (*PendingRefreshesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PendingRefreshes not implemented")
+}
+func (UnimplementedTrackerInfoAdminServer) ConfirmRefresh(context.Context, *SourceRequest) (*EmptyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmRefresh not implemented")
+}
+func (UnimplementedTrackerInfoAdminServer) DiscardRefresh(context.Context, *SourceRequest) (*EmptyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscardRefresh not implemented")
+}
+func (UnimplementedTrackerInfoAdminServer) mustEmbedUnimplementedTrackerInfoAdminServer() {}
+
+// UnsafeTrackerInfoAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TrackerInfoAdminServer will
+// result in compilation errors.
+type UnsafeTrackerInfoAdminServer interface {
+	mustEmbedUnimplementedTrackerInfoAdminServer()
+}
+
+func RegisterTrackerInfoAdminServer(s grpc.ServiceRegistrar, srv TrackerInfoAdminServer) {
+	s.RegisterService(&TrackerInfoAdmin_ServiceDesc, srv)
+}
+
+func _TrackerInfoAdmin_PendingRefreshes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerInfoAdminServer).PendingRefreshes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerInfoAdmin_PendingRefreshes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerInfoAdminServer).PendingRefreshes(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerInfoAdmin_ConfirmRefresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerInfoAdminServer).ConfirmRefresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerInfoAdmin_ConfirmRefresh_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerInfoAdminServer).ConfirmRefresh(ctx, req.(*SourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerInfoAdmin_DiscardRefresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerInfoAdminServer).DiscardRefresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerInfoAdmin_DiscardRefresh_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerInfoAdminServer).DiscardRefresh(ctx, req.(*SourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TrackerInfoAdmin_ServiceDesc is the grpc.ServiceDesc for TrackerInfoAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TrackerInfoAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "trackerinfo.TrackerInfoAdmin",
+	HandlerType: (*TrackerInfoAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PendingRefreshes",
+			Handler:    _TrackerInfoAdmin_PendingRefreshes_Handler,
+		},
+		{
+			MethodName: "ConfirmRefresh",
+			Handler:    _TrackerInfoAdmin_ConfirmRefresh_Handler,
+		},
+		{
+			MethodName: "DiscardRefresh",
+			Handler:    _TrackerInfoAdmin_DiscardRefresh_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "trackerinfo.proto",
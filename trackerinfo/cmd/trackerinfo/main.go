@@ -65,11 +65,7 @@ func main() {
 	// if metric.Init wasn't called before, global meter provider returns noop instance
 	meter := otel.GetMeterProvider().Meter(serviceName)
 
-	app, err := app.New(ctx, log, tracer, meter,
-		cfg.HTTPClient.Timeout,
-		cfg.Fetchers.UpdateInterval,
-		cfg.GRPCServer.Port,
-		cfg.Storage.Path)
+	app, err := app.New(ctx, log, tracer, meter, cfg)
 	if err != nil {
 		panic(err)
 	}
@@ -2,14 +2,19 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
-	"time"
 
+	sl "github.com/MRibalko/smogtracker/pkg/logger"
 	"github.com/MRibalko/smogtracker/trackerinfo/internal/app/grpcapp"
-	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/armaqi"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/app/httpapp"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/config"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
 	"github.com/MRibalko/smogtracker/trackerinfo/internal/services/trackerlist"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage/memory"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage/postgres"
 	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage/sqlite"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
@@ -17,22 +22,23 @@ import (
 
 type App struct {
 	gRPCApp *grpcapp.App
-	ctx     context.Context
-	service *trackerlist.TrackerList
-	log     *slog.Logger
+	// nil unless the http server is enabled
+	httpApp *httpapp.App
+	// fetchers started and stopped along with the app
+	connected []connectedFetcher
+	ctx       context.Context
+	service   *trackerlist.TrackerList
+	log       *slog.Logger
 }
 
 func New(ctx context.Context,
 	log *slog.Logger,
 	tracer trace.Tracer,
 	meter metric.Meter,
-	httpTimeout time.Duration,
-	updateInterval time.Duration,
-	grpcPort int,
-	storagePath string,
+	cfg *config.Config,
 ) (*App, error) {
 	const op = "app.New"
-	storage, err := sqlite.New(tracer, sqlite.WithStoragePath(storagePath))
+	storage, err := newStorage(tracer, cfg.Storage)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -42,34 +48,120 @@ func New(ctx context.Context,
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	httpClient := &http.Client{Timeout: httpTimeout}
+	httpClient := &http.Client{Timeout: cfg.HTTPClient.Timeout}
 
-	armaqiFetcher := armaqi.New(httpClient, updateInterval)
-	err = trackerListService.RegisterSource(armaqiFetcher)
+	fetchers, err := newFetchers(log, httpClient, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	grpcApp := grpcapp.New(log, trackerListService, grpcPort)
+	fetchers, err = withRetries(log, meter, fetchers, cfg.Fetchers.Retry)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var connected []connectedFetcher
+	for _, f := range fetchers {
+		if err := trackerListService.RegisterSource(f); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if c, ok := asConnected(f); ok {
+			connected = append(connected, c)
+		}
+	}
+
+	for source, guard := range cfg.Fetchers.DeletionGuards {
+		err := trackerListService.SetDeletionGuard(models.SourceName(source), trackerlist.DeletionGuard{
+			MaxPercent: guard.MaxPercent,
+			MaxCount:   guard.MaxCount,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	for source, meters := range cfg.Fetchers.JitterThresholds {
+		if err := trackerListService.SetJitterThreshold(models.SourceName(source), meters); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	for source, breaker := range cfg.Fetchers.CircuitBreakers {
+		err := trackerListService.SetCircuitBreaker(models.SourceName(source), trackerlist.CircuitBreaker{
+			FailureThreshold: breaker.FailureThreshold,
+			CoolDown:         breaker.CoolDown,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	grpcApp := grpcapp.New(log, trackerListService, trackerListService, cfg.GRPCServer.AdminToken, cfg.GRPCServer.Port)
+
+	var httpApp *httpapp.App
+	if cfg.HTTPServer.Enabled {
+		tokens := make(map[string]string, len(cfg.Fetchers.Push))
+		for _, src := range cfg.Fetchers.Push {
+			tokens[src.Name] = src.Token
+		}
+		httpApp = httpapp.New(log, trackerListService, tokens, cfg.HTTPServer.Port, cfg.HTTPServer.Timeout)
+	}
 
 	return &App{
-		gRPCApp: grpcApp,
-		ctx:     ctx,
-		service: trackerListService,
-		log:     log}, nil
+		gRPCApp:   grpcApp,
+		httpApp:   httpApp,
+		connected: connected,
+		ctx:       ctx,
+		service:   trackerListService,
+		log:       log}, nil
+
+}
 
+func newStorage(tracer trace.Tracer, cfg config.Storage) (trackerlist.Storage, error) {
+	switch cfg.Type {
+	case config.StorageSqlite:
+		if cfg.Path == "" {
+			return nil, errors.New("storage path is required")
+		}
+		return sqlite.New(tracer, sqlite.WithStoragePath(cfg.Path))
+	case config.StoragePostgres:
+		if cfg.DSN == "" {
+			return nil, errors.New("storage dsn is required")
+		}
+		return postgres.New(tracer, postgres.WithDSN(cfg.DSN))
+	case config.StorageMemory:
+		return memory.New(tracer), nil
+	default:
+		return nil, fmt.Errorf("unknown storage type %q", cfg.Type)
+	}
 }
 
 func (a *App) Start() {
+	for _, c := range a.connected {
+		if err := c.Start(); err != nil {
+			a.log.Error("fetcher start failed", sl.Err(err))
+		}
+	}
 	a.service.StartUpdate(a.ctx)
 	go a.gRPCApp.MustStart()
+	if a.httpApp != nil {
+		go a.httpApp.MustStart()
+	}
 	a.log.Info("application started")
 
 }
 
 func (a *App) Shutdown(ctx context.Context) error {
 	a.service.StopUpdate()
+	for _, c := range a.connected {
+		c.Stop()
+	}
 	a.gRPCApp.Stop()
+	if a.httpApp != nil {
+		if err := a.httpApp.Stop(ctx); err != nil {
+			return err
+		}
+	}
 	a.log.Info("application stopped")
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/MRibalko/smogtracker/pkg/logger/slogdiscard"
 	"github.com/MRibalko/smogtracker/protos/gen/trackerinfov1"
 	"github.com/MRibalko/smogtracker/trackerinfo/internal/app"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/config"
 	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/sqlite3"
@@ -23,7 +25,9 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -59,10 +63,25 @@ func TestApp_LoadAndDisplay(t *testing.T) {
 		]
 	  }`
 
+		infoJSON = `{
+		"station": {
+		  "id": 76921,
+		  "title": "Kentron",
+		  "position": {
+			"lat": 40.182,
+			"lng": 44.516
+		  },
+		  "pm25": 15,
+		  "pm10": 10,
+		  "lastUpdated": "2024-03-08T10:37:33Z"
+		}
+	  }`
+
 		migrationPath = "../../migrations"
 		storagePath   = "../../storage/testStorage.db"
 		grpcPort      = 44443
 		url           = "https://armaqi.org/api/waqi/list"
+		infoUrl       = "https://armaqi.org/api/waqi/info?id=76921"
 	)
 
 	ctx := context.Background()
@@ -71,8 +90,11 @@ func TestApp_LoadAndDisplay(t *testing.T) {
 	http.DefaultTransport = RoundTripFunc(func(req *http.Request) *http.Response {
 		var body io.ReadCloser
 
-		if strings.Compare(req.URL.String(), url) == 0 {
+		switch req.URL.String() {
+		case url:
 			body = io.NopCloser(strings.NewReader(respJSON))
+		case infoUrl:
+			body = io.NopCloser(strings.NewReader(infoJSON))
 		}
 
 		return &http.Response{
@@ -83,6 +105,9 @@ func TestApp_LoadAndDisplay(t *testing.T) {
 	})
 
 	// create test db
+	err := os.MkdirAll(filepath.Dir(storagePath), 0o755)
+	require.NoError(t, err)
+
 	db, err := sql.Open("sqlite3", storagePath)
 	require.NoError(t, err)
 
@@ -100,14 +125,17 @@ func TestApp_LoadAndDisplay(t *testing.T) {
 	})
 
 	// create and start test app
+	cfg := &config.Config{}
+	cfg.Storage = config.Storage{Type: config.StorageSqlite, Path: storagePath}
+	cfg.GRPCServer.Port = grpcPort
+	cfg.HTTPClient.Timeout = 10 * time.Second
+	cfg.Fetchers.UpdateInterval = 10 * time.Minute
+
 	app, err := app.New(ctx,
 		slogdiscard.NewDiscardLogger(),
 		otel.Tracer("test"),
 		otel.Meter("test"),
-		10*time.Second,
-		10*time.Minute,
-		grpcPort,
-		storagePath)
+		cfg)
 	require.NoError(t, err)
 
 	app.Start()
@@ -121,6 +149,12 @@ func TestApp_LoadAndDisplay(t *testing.T) {
 
 	grpcClient := trackerinfov1.NewTrackerInfoClient(conn)
 
+	// wait for the first update of the source
+	require.Eventually(t, func() bool {
+		resp, err := grpcClient.List(ctx, &trackerinfov1.ModifiedFromRequest{})
+		return err == nil && len(resp.Result) == 2
+	}, 5*time.Second, 50*time.Millisecond)
+
 	t.Run("List", func(t *testing.T) {
 
 		resp, err := grpcClient.List(ctx, &trackerinfov1.ModifiedFromRequest{})
@@ -212,4 +246,140 @@ func TestApp_LoadAndDisplay(t *testing.T) {
 
 	})
 
+	t.Run("Readings", func(t *testing.T) {
+		observedAt := time.Date(2024, 3, 8, 10, 37, 33, 0, time.UTC)
+
+		resp, err := grpcClient.Readings(ctx, &trackerinfov1.ReadingsRequest{
+			Source: "armaqi",
+			OrigId: "76921",
+			From:   timestamppb.New(observedAt.Add(-24 * time.Hour)),
+			To:     timestamppb.New(observedAt.Add(time.Second)),
+		})
+		require.NoError(t, err)
+
+		want := []models.Measurement{
+			{
+				TrackerId:  "armaqi|76921",
+				Pollutant:  models.PM10,
+				Value:      10,
+				Unit:       models.UnitMicrogramsPerCubicMeter,
+				ObservedAt: observedAt,
+			},
+			{
+				TrackerId:  "armaqi|76921",
+				Pollutant:  models.PM25,
+				Value:      15,
+				Unit:       models.UnitMicrogramsPerCubicMeter,
+				ObservedAt: observedAt,
+			},
+		}
+
+		got := make([]models.Measurement, 0, len(resp.Result))
+
+		for _, v := range resp.Result {
+			got = append(got, models.Measurement{
+				TrackerId:  "armaqi|76921",
+				Pollutant:  models.Pollutant(v.Pollutant),
+				Value:      v.Value,
+				Unit:       v.Unit,
+				ObservedAt: v.ObservedAt.AsTime(),
+			})
+		}
+
+		require.Equal(t, want, got)
+
+		_, err = grpcClient.Readings(ctx, &trackerinfov1.ReadingsRequest{
+			Source:    "armaqi",
+			OrigId:    "76921",
+			Pollutant: string(models.PM25),
+			From:      timestamppb.New(observedAt.Add(time.Second)),
+			To:        timestamppb.New(observedAt.Add(time.Hour)),
+		})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("List changes after revision", func(t *testing.T) {
+		full, err := grpcClient.List(ctx, &trackerinfov1.ModifiedFromRequest{})
+		require.NoError(t, err)
+		require.Positive(t, full.Revision)
+
+		resp, err := grpcClient.List(ctx, &trackerinfov1.ModifiedFromRequest{AfterRevision: full.Revision - 1})
+		require.NoError(t, err)
+		require.Len(t, resp.Result, 1)
+		require.Equal(t, full.Revision, resp.Revision)
+
+		_, err = grpcClient.List(ctx, &trackerinfov1.ModifiedFromRequest{AfterRevision: full.Revision})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Watch replay", func(t *testing.T) {
+		wctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		stream, err := grpcClient.Watch(wctx, &trackerinfov1.WatchRequest{
+			Source:        "armaqi",
+			AfterRevision: 1,
+		})
+		require.NoError(t, err)
+
+		ev, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, trackerinfov1.ChangeType_CHANGE_TYPE_UPDATED, ev.Type)
+		require.Equal(t, "397555", ev.Tracker.OrigId)
+		require.Equal(t, int64(2), ev.Tracker.Revision)
+	})
+
+	t.Run("Tracker history", func(t *testing.T) {
+		resp, err := grpcClient.TrackerHistory(ctx, &trackerinfov1.TrackerRequest{
+			Source: "armaqi",
+			OrigId: "76921",
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Result, 1)
+		require.Equal(t, trackerinfov1.VersionKind_VERSION_KIND_CREATED, resp.Result[0].Kind)
+		require.Equal(t, "Kentron", resp.Result[0].Tracker.Description)
+		require.NotNil(t, resp.Result[0].ValidFrom)
+		require.Nil(t, resp.Result[0].ValidTo)
+
+		_, err = grpcClient.TrackerHistory(ctx, &trackerinfov1.TrackerRequest{
+			Source: "armaqi",
+			OrigId: "unknown",
+		})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Source status", func(t *testing.T) {
+		resp, err := grpcClient.SourceStatus(ctx, &trackerinfov1.SourceRequest{})
+		require.NoError(t, err, "served without the admin token")
+		require.Len(t, resp.Result, 1)
+		require.Equal(t, "armaqi", resp.Result[0].Source)
+		require.Equal(t, trackerinfov1.SourceState_SOURCE_STATE_HEALTHY, resp.Result[0].State)
+		require.NotNil(t, resp.Result[0].LastSuccess)
+
+		_, err = grpcClient.SourceStatus(ctx, &trackerinfov1.SourceRequest{Source: "unknown"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+}
+
+func TestApp_PushRequiresHTTPServer(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Storage = config.Storage{Type: config.StorageMemory}
+	cfg.Fetchers.UpdateInterval = 10 * time.Minute
+	cfg.Fetchers.Push = []config.PushSource{{Name: "partner", Token: "secret"}}
+
+	_, err := app.New(context.Background(),
+		slogdiscard.NewDiscardLogger(),
+		otel.Tracer("test"),
+		otel.Meter("test"),
+		cfg)
+	require.ErrorContains(t, err, "http server")
+
+	cfg.HTTPServer.Enabled = true
+	_, err = app.New(context.Background(),
+		slogdiscard.NewDiscardLogger(),
+		otel.Tracer("test"),
+		otel.Meter("test"),
+		cfg)
+	require.NoError(t, err)
 }
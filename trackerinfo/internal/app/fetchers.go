@@ -0,0 +1,191 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/config"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/armaqi"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/csvfile"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/jsonhttp"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/mqttsource"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/openaq"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/push"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/retry"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/sensorcommunity"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/services/trackerlist"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Creates the built-in fetchers and the ones described by the config
+func newFetchers(log *slog.Logger, client *http.Client, cfg *config.Config) ([]trackerlist.Fetcher, error) {
+	fetchers := []trackerlist.Fetcher{
+		armaqi.New(client, cfg.Fetchers.UpdateInterval),
+	}
+
+	if oaq := cfg.Fetchers.OpenAQ; oaq.Enabled {
+		fetchers = append(fetchers, openaq.New(client, openaq.Config{
+			URL:            oaq.URL,
+			APIKey:         oaq.APIKey,
+			PageSize:       oaq.PageSize,
+			UpdateInterval: updateInterval(oaq.UpdateInterval, cfg),
+			Query:          oaq.Query,
+		}))
+	}
+
+	if sc := cfg.Fetchers.SensorCommunity; sc.Enabled {
+		fetchers = append(fetchers, sensorcommunity.New(client, sc.URL, updateInterval(sc.UpdateInterval, cfg)))
+	}
+
+	for _, src := range cfg.Fetchers.JSONHTTP {
+		f, err := jsonhttp.New(log, client, jsonhttp.Config{
+			Name:           src.Name,
+			URL:            src.URL,
+			Method:         src.Method,
+			Headers:        src.Headers,
+			Body:           src.Body,
+			UpdateInterval: updateInterval(src.UpdateInterval, cfg),
+			Items:          src.Items,
+			Fields: jsonhttp.Fields{
+				Id:        src.Fields.Id,
+				Title:     src.Fields.Title,
+				Latitude:  src.Fields.Latitude,
+				Longitude: src.Fields.Longitude,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		fetchers = append(fetchers, f)
+	}
+
+	for _, src := range cfg.Fetchers.CSV {
+		f, err := csvfile.New(log, client, csvfile.Config{
+			Name:           src.Name,
+			Path:           src.Path,
+			URL:            src.URL,
+			UpdateInterval: updateInterval(src.UpdateInterval, cfg),
+			Delimiter:      src.Delimiter,
+			Header:         src.Header,
+			Columns: csvfile.Columns{
+				Id:        src.Columns.Id,
+				Title:     src.Columns.Title,
+				Latitude:  src.Columns.Latitude,
+				Longitude: src.Columns.Longitude,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		fetchers = append(fetchers, f)
+	}
+
+	for _, src := range cfg.Fetchers.MQTT {
+		f, err := mqttsource.New(log, mqttsource.Config{
+			Name:           src.Name,
+			Broker:         src.Broker,
+			ClientId:       src.ClientId,
+			Username:       src.Username,
+			Password:       src.Password,
+			Topics:         src.Topics,
+			QoS:            src.QoS,
+			UpdateInterval: updateInterval(src.UpdateInterval, cfg),
+			StaleAfter:     src.StaleAfter,
+			MinBackoff:     src.MinBackoff,
+			MaxBackoff:     src.MaxBackoff,
+			Template: mqttsource.Template{
+				Id:         src.Template.Id,
+				Title:      src.Template.Title,
+				Latitude:   src.Template.Latitude,
+				Longitude:  src.Template.Longitude,
+				ObservedAt: src.Template.ObservedAt,
+				Readings:   src.Template.Readings,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		fetchers = append(fetchers, f)
+	}
+
+	// without the http server nothing would ever be pushed to the sources
+	if len(cfg.Fetchers.Push) != 0 && !cfg.HTTPServer.Enabled {
+		return nil, errors.New("push sources require the http server to be enabled")
+	}
+	for _, src := range cfg.Fetchers.Push {
+		if len(src.Token) == 0 {
+			return nil, fmt.Errorf("push source %s: token is required", src.Name)
+		}
+		fetchers = append(fetchers, push.New(src.Name))
+	}
+
+	return fetchers, nil
+}
+
+// Wraps the fetchers having a retry policy in the config
+func withRetries(log *slog.Logger,
+	meter metric.Meter,
+	fetchers []trackerlist.Fetcher,
+	policies map[string]config.RetryPolicy,
+) ([]trackerlist.Fetcher, error) {
+	res := make([]trackerlist.Fetcher, 0, len(fetchers))
+	wrapped := make(map[string]bool, len(policies))
+
+	for _, f := range fetchers {
+		policy, exists := policies[string(f.Name())]
+		if !exists {
+			res = append(res, f)
+			continue
+		}
+
+		r, err := retry.New(log, meter, f, retry.Policy{
+			MaxAttempts: policy.MaxAttempts,
+			BaseDelay:   policy.BaseDelay,
+			MaxDelay:    policy.MaxDelay,
+		})
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, r)
+		wrapped[string(f.Name())] = true
+	}
+
+	for source := range policies {
+		if !wrapped[source] {
+			return nil, fmt.Errorf("retry policy of unknown source %s", source)
+		}
+	}
+
+	return res, nil
+}
+
+// Fetchers holding a connection, they are started and stopped along with the app
+type connectedFetcher interface {
+	Start() error
+	Stop()
+}
+
+// Looks for a connected fetcher through the wrapped ones
+func asConnected(f trackerlist.Fetcher) (connectedFetcher, bool) {
+	for {
+		if c, ok := f.(connectedFetcher); ok {
+			return c, true
+		}
+		w, ok := f.(trackerlist.WrappingFetcher)
+		if !ok {
+			return nil, false
+		}
+		f = w.Unwrap()
+	}
+}
+
+// Sources without their own interval are updated with the common one
+func updateInterval(interval time.Duration, cfg *config.Config) time.Duration {
+	if interval == 0 {
+		return cfg.Fetchers.UpdateInterval
+	}
+	return interval
+}
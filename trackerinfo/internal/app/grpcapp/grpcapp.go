@@ -21,7 +21,13 @@ type App struct {
 	port       int
 }
 
-func New(log *slog.Logger, trackerInfoService trackerinfogrpc.TrackerInfo, port int) *App {
+func New(log *slog.Logger,
+	trackerInfoService trackerinfogrpc.TrackerInfo,
+	adminService trackerinfogrpc.Admin,
+	// the admin service isn't served if empty
+	adminToken string,
+	port int,
+) *App {
 	logOptions := []logging.Option{
 		logging.WithLogOnEvents(logging.StartCall, logging.FinishCall),
 		logging.WithDurationField(logging.DefaultDurationToFields),
@@ -39,9 +45,20 @@ func New(log *slog.Logger, trackerInfoService trackerinfogrpc.TrackerInfo, port
 		grpc.ChainUnaryInterceptor(
 			recovery.UnaryServerInterceptor(recOptions...),
 			logging.UnaryServerInterceptor(InterceptorLogger(log), logOptions...),
+			trackerinfogrpc.AdminAuthInterceptor(adminToken),
+		),
+		grpc.ChainStreamInterceptor(
+			recovery.StreamServerInterceptor(recOptions...),
+			logging.StreamServerInterceptor(InterceptorLogger(log), logOptions...),
+			trackerinfogrpc.AdminAuthStreamInterceptor(adminToken),
 		))
 
 	trackerinfogrpc.Register(gRPCServer, trackerInfoService)
+	if len(adminToken) != 0 {
+		trackerinfogrpc.RegisterAdmin(gRPCServer, adminService)
+	} else {
+		log.Info("grpc admin service disabled, admin token is not set")
+	}
 
 	return &App{
 		log:        log,
@@ -0,0 +1,73 @@
+package httpapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/httpapi"
+)
+
+type App struct {
+	log        *slog.Logger
+	httpServer *http.Server
+	port       int
+}
+
+// Serves the ingestion endpoint of push sources, tokens are keyed by source name
+func New(log *slog.Logger,
+	ingester httpapi.Ingester,
+	tokens map[string]string,
+	port int,
+	timeout time.Duration,
+) *App {
+	mux := http.NewServeMux()
+	httpapi.RegisterIngest(mux, log, ingester, tokens)
+
+	return &App{
+		log: log,
+		httpServer: &http.Server{
+			Addr:              fmt.Sprintf(":%d", port),
+			Handler:           mux,
+			ReadHeaderTimeout: timeout,
+			ReadTimeout:       timeout,
+			WriteTimeout:      timeout,
+		},
+		port: port,
+	}
+}
+
+func (a *App) Start() error {
+	const op = "httpapp.Start"
+
+	l, err := net.Listen("tcp", a.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.log.Info("http server started", slog.String("address", l.Addr().String()))
+
+	if err := a.httpServer.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (a *App) MustStart() {
+	if err := a.Start(); err != nil {
+		panic(err)
+	}
+}
+
+func (a *App) Stop(ctx context.Context) error {
+	const op = "httpapp.Stop"
+	a.log.With(slog.String("op", op)).
+		Info("stopping http server", slog.Int("port", a.port))
+
+	return a.httpServer.Shutdown(ctx)
+}
@@ -0,0 +1,164 @@
+package aqi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+)
+
+type (
+	Scale string
+
+	Index struct {
+		Value    int
+		Category string
+		Dominant models.Pollutant
+	}
+
+	// Concentration range [concLow, concHigh] mapped linearly to index range [indexLow, indexHigh]
+	breakpoint struct {
+		concLow   float64
+		concHigh  float64
+		indexLow  float64
+		indexHigh float64
+		category  string
+	}
+
+	scaleTable struct {
+		breakpoints map[models.Pollutant][]breakpoint
+		// rounds a concentration before the lookup the way the scale prescribes
+		round map[models.Pollutant]func(float64) float64
+	}
+)
+
+const (
+	// US EPA Air Quality Index, PM breakpoints as revised in 2024
+	USEPA Scale = "us-epa"
+	// European Common Air Quality Index, hourly background grid
+	CAQI Scale = "caqi"
+)
+
+var (
+	ErrUnknownScale         = errors.New("unknown scale")
+	ErrUnsupportedPollutant = errors.New("unsupported pollutant")
+	ErrNegativeValue        = errors.New("negative concentration")
+	ErrNoData               = errors.New("no concentrations")
+)
+
+var scales = map[Scale]scaleTable{
+	USEPA: {
+		breakpoints: map[models.Pollutant][]breakpoint{
+			models.PM25: {
+				{0, 9.0, 0, 50, "Good"},
+				{9.1, 35.4, 51, 100, "Moderate"},
+				{35.5, 55.4, 101, 150, "Unhealthy for Sensitive Groups"},
+				{55.5, 125.4, 151, 200, "Unhealthy"},
+				{125.5, 225.4, 201, 300, "Very Unhealthy"},
+				{225.5, 325.4, 301, 500, "Hazardous"},
+			},
+			models.PM10: {
+				{0, 54, 0, 50, "Good"},
+				{55, 154, 51, 100, "Moderate"},
+				{155, 254, 101, 150, "Unhealthy for Sensitive Groups"},
+				{255, 354, 151, 200, "Unhealthy"},
+				{355, 424, 201, 300, "Very Unhealthy"},
+				{425, 604, 301, 500, "Hazardous"},
+			},
+		},
+		round: map[models.Pollutant]func(float64) float64{
+			models.PM25: func(c float64) float64 { return math.Floor(c*10+1e-9) / 10 },
+			models.PM10: math.Floor,
+		},
+	},
+	// the grid defines no upper limit of "Very high",
+	// its band continues the slope of "High"
+	CAQI: {
+		breakpoints: map[models.Pollutant][]breakpoint{
+			models.PM25: {
+				{0, 15, 0, 25, "Very low"},
+				{15, 30, 25, 50, "Low"},
+				{30, 55, 50, 75, "Medium"},
+				{55, 110, 75, 100, "High"},
+				{110, 165, 100, 125, "Very high"},
+			},
+			models.PM10: {
+				{0, 25, 0, 25, "Very low"},
+				{25, 50, 25, 50, "Low"},
+				{50, 90, 50, 75, "Medium"},
+				{90, 180, 75, 100, "High"},
+				{180, 270, 100, 125, "Very high"},
+			},
+		},
+	},
+}
+
+// Calculates the index of every pollutant and returns the highest one.
+// The pollutant with the highest index is reported as the dominant one
+func Calculate(scale Scale, concentrations map[models.Pollutant]float64) (Index, error) {
+	const op = "aqi.Calculate"
+
+	if len(concentrations) == 0 {
+		return Index{}, fmt.Errorf("%s: %w", op, ErrNoData)
+	}
+
+	var (
+		res   Index
+		found bool
+	)
+
+	for pollutant, concentration := range concentrations {
+		index, err := SubIndex(scale, pollutant, concentration)
+		if err != nil {
+			return Index{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		// ties are resolved by the pollutant name to keep the result stable
+		if !found || index.Value > res.Value ||
+			(index.Value == res.Value && index.Dominant < res.Dominant) {
+			res = index
+			found = true
+		}
+	}
+
+	return res, nil
+}
+
+// Calculates the index of a single pollutant concentration in µg/m³.
+// Concentrations above the last breakpoint are extrapolated from its slope
+func SubIndex(scale Scale, pollutant models.Pollutant, concentration float64) (Index, error) {
+	table, exists := scales[scale]
+	if !exists {
+		return Index{}, fmt.Errorf("%w: %s", ErrUnknownScale, scale)
+	}
+
+	breakpoints, exists := table.breakpoints[pollutant]
+	if !exists {
+		return Index{}, fmt.Errorf("%w: %s", ErrUnsupportedPollutant, pollutant)
+	}
+
+	if concentration < 0 || math.IsNaN(concentration) {
+		return Index{}, fmt.Errorf("%w: %s %f", ErrNegativeValue, pollutant, concentration)
+	}
+
+	if round, exists := table.round[pollutant]; exists {
+		concentration = round(concentration)
+	}
+
+	bp := breakpoints[len(breakpoints)-1]
+	for _, v := range breakpoints {
+		if concentration <= v.concHigh {
+			bp = v
+			break
+		}
+	}
+
+	value := (bp.indexHigh-bp.indexLow)/(bp.concHigh-bp.concLow)*(concentration-bp.concLow) + bp.indexLow
+
+	return Index{
+		Value:    int(math.Round(value)),
+		Category: bp.category,
+		Dominant: pollutant,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package aqi_test
+
+import (
+	"testing"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/aqi"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubIndex(t *testing.T) {
+	cases := []struct {
+		name          string
+		scale         aqi.Scale
+		pollutant     models.Pollutant
+		concentration float64
+		want          aqi.Index
+	}{
+		{"epa pm25 zero", aqi.USEPA, models.PM25, 0, aqi.Index{0, "Good", models.PM25}},
+		{"epa pm25 good upper", aqi.USEPA, models.PM25, 9.0, aqi.Index{50, "Good", models.PM25}},
+		{"epa pm25 truncated", aqi.USEPA, models.PM25, 9.09, aqi.Index{50, "Good", models.PM25}},
+		{"epa pm25 moderate lower", aqi.USEPA, models.PM25, 9.1, aqi.Index{51, "Moderate", models.PM25}},
+		{"epa pm25 moderate", aqi.USEPA, models.PM25, 15, aqi.Index{62, "Moderate", models.PM25}},
+		{"epa pm25 unhealthy", aqi.USEPA, models.PM25, 55.5, aqi.Index{151, "Unhealthy", models.PM25}},
+		{"epa pm25 hazardous upper", aqi.USEPA, models.PM25, 325.4, aqi.Index{500, "Hazardous", models.PM25}},
+		{"epa pm25 beyond scale", aqi.USEPA, models.PM25, 500, aqi.Index{848, "Hazardous", models.PM25}},
+		{"epa pm10 good", aqi.USEPA, models.PM10, 10, aqi.Index{9, "Good", models.PM10}},
+		{"epa pm10 truncated", aqi.USEPA, models.PM10, 54.9, aqi.Index{50, "Good", models.PM10}},
+		{"epa pm10 sensitive groups", aqi.USEPA, models.PM10, 200, aqi.Index{123, "Unhealthy for Sensitive Groups", models.PM10}},
+		{"caqi pm25 very low", aqi.CAQI, models.PM25, 15, aqi.Index{25, "Very low", models.PM25}},
+		{"caqi pm25 medium", aqi.CAQI, models.PM25, 42.5, aqi.Index{63, "Medium", models.PM25}},
+		{"caqi pm25 very high", aqi.CAQI, models.PM25, 220, aqi.Index{150, "Very high", models.PM25}},
+		{"caqi pm10 low", aqi.CAQI, models.PM10, 40, aqi.Index{40, "Low", models.PM10}},
+		{"caqi pm10 high", aqi.CAQI, models.PM10, 135, aqi.Index{88, "High", models.PM10}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := aqi.SubIndex(tt.scale, tt.pollutant, tt.concentration)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSubIndex_Errors(t *testing.T) {
+	_, err := aqi.SubIndex("unknown", models.PM25, 1)
+	require.ErrorIs(t, err, aqi.ErrUnknownScale)
+
+	_, err = aqi.SubIndex(aqi.USEPA, "o3", 1)
+	require.ErrorIs(t, err, aqi.ErrUnsupportedPollutant)
+
+	_, err = aqi.SubIndex(aqi.CAQI, models.PM10, -1)
+	require.ErrorIs(t, err, aqi.ErrNegativeValue)
+}
+
+func TestCalculate(t *testing.T) {
+	t.Run("dominant pollutant", func(t *testing.T) {
+		got, err := aqi.Calculate(aqi.USEPA, map[models.Pollutant]float64{
+			models.PM25: 15,
+			models.PM10: 10,
+		})
+		require.NoError(t, err)
+		require.Equal(t, aqi.Index{62, "Moderate", models.PM25}, got)
+
+		got, err = aqi.Calculate(aqi.CAQI, map[models.Pollutant]float64{
+			models.PM25: 15,
+			models.PM10: 40,
+		})
+		require.NoError(t, err)
+		require.Equal(t, aqi.Index{40, "Low", models.PM10}, got)
+	})
+
+	t.Run("equal indexes", func(t *testing.T) {
+		got, err := aqi.Calculate(aqi.CAQI, map[models.Pollutant]float64{
+			models.PM25: 15,
+			models.PM10: 25,
+		})
+		require.NoError(t, err)
+		require.Equal(t, aqi.Index{25, "Very low", models.PM10}, got)
+	})
+
+	t.Run("no data", func(t *testing.T) {
+		_, err := aqi.Calculate(aqi.CAQI, nil)
+		require.ErrorIs(t, err, aqi.ErrNoData)
+	})
+}
@@ -11,19 +11,39 @@ import (
 
 type (
 	Config struct {
-		Env     string `yaml:"env" env-default:"local"`
-		Storage struct {
-			Path string `yaml:"path" env-required:"true"`
-		} `yaml:"storage"`
+		Env        string  `yaml:"env" env-default:"local"`
+		Storage    Storage `yaml:"storage"`
 		GRPCServer struct {
 			Port    int           `yaml:"port" env:"GRPC_SERVER_PORT" env-required:"true"`
 			Timeout time.Duration `yaml:"timeout" env-default:"5s"`
+			// bearer token of the admin service, the service is disabled if empty
+			AdminToken string `yaml:"admin_token" env:"GRPC_ADMIN_TOKEN"`
 		} `yaml:"grpc_server"`
+		// serves the ingestion endpoint of push sources
+		HTTPServer struct {
+			Enabled bool          `yaml:"enabled" env-default:"false"`
+			Port    int           `yaml:"port" env:"HTTP_SERVER_PORT" env-default:"8080"`
+			Timeout time.Duration `yaml:"timeout" env-default:"10s"`
+		} `yaml:"http_server"`
 		HTTPClient struct {
 			Timeout time.Duration `yaml:"timeout" env-default:"10s"`
 		} `yaml:"http_client"`
 		Fetchers struct {
 			UpdateInterval time.Duration `yaml:"update_interval" env-default:"10m"`
+			// keyed by source name, sources without a guard apply any refresh
+			DeletionGuards map[string]DeletionGuard `yaml:"deletion_guards"`
+			// meters keyed by source name, coordinate changes within the threshold are ignored
+			JitterThresholds map[string]float64 `yaml:"jitter_thresholds"`
+			// keyed by source name, sources without a policy aren't retried until the next update
+			Retry map[string]RetryPolicy `yaml:"retry"`
+			// keyed by source name, the rest of the sources use the default breaker
+			CircuitBreakers map[string]CircuitBreaker `yaml:"circuit_breakers"`
+			JSONHTTP        []JSONHTTPSource          `yaml:"json_http"`
+			CSV             []CSVSource               `yaml:"csv"`
+			OpenAQ          OpenAQ                    `yaml:"openaq"`
+			SensorCommunity SensorCommunity           `yaml:"sensor_community"`
+			Push            []PushSource              `yaml:"push"`
+			MQTT            []MQTTSource              `yaml:"mqtt"`
 		} `yaml:"fetchers"`
 		Tracing struct {
 			Enabled     bool   `yaml:"enabled" env-default:"false"`
@@ -36,6 +56,119 @@ type (
 			} `yaml:"http_server"`
 		} `yaml:"metrics"`
 	}
+	// Type selects the backend: sqlite uses Path, postgres uses DSN,
+	// memory keeps nothing between restarts
+	Storage struct {
+		Type string `yaml:"type" env:"STORAGE_TYPE" env-default:"sqlite"`
+		Path string `yaml:"path" env:"STORAGE_PATH"`
+		DSN  string `yaml:"dsn" env:"STORAGE_DSN"`
+	}
+	// Source publishing its stations as a JSON document. Paths are JSONPath-like:
+	// $.data.stations[*], position.lat, ['station name']
+	JSONHTTPSource struct {
+		Name    string            `yaml:"name"`
+		URL     string            `yaml:"url"`
+		Method  string            `yaml:"method"`
+		Headers map[string]string `yaml:"headers"`
+		Body    string            `yaml:"body"`
+		// the common update interval is used if zero
+		UpdateInterval time.Duration `yaml:"update_interval"`
+		Items          string        `yaml:"items"`
+		Fields         struct {
+			Id        string `yaml:"id"`
+			Title     string `yaml:"title"`
+			Latitude  string `yaml:"latitude"`
+			Longitude string `yaml:"longitude"`
+		} `yaml:"fields"`
+	}
+	// Built-in source registered if enabled, v3 of the API requires the key
+	OpenAQ struct {
+		Enabled bool `yaml:"enabled" env-default:"false"`
+		// locations endpoint of v2 or v3, v3 if empty
+		URL            string        `yaml:"url"`
+		APIKey         string        `yaml:"api_key" env:"OPENAQ_API_KEY"`
+		PageSize       int           `yaml:"page_size"`
+		UpdateInterval time.Duration `yaml:"update_interval"`
+		// filters like countries_id or bbox
+		Query map[string]string `yaml:"query"`
+	}
+	// Built-in source of citizen sensors registered if enabled
+	SensorCommunity struct {
+		Enabled bool `yaml:"enabled" env-default:"false"`
+		// the global feed if empty
+		URL            string        `yaml:"url"`
+		UpdateInterval time.Duration `yaml:"update_interval"`
+	}
+	// Passive source of a partner posting batches to the ingestion endpoint
+	PushSource struct {
+		Name string `yaml:"name"`
+		// bearer token of the partner
+		Token string `yaml:"token"`
+	}
+	// Stations heard from on the broker, payloads are mapped by the template.
+	// Template fields are JSONPath-like paths, topic[n] takes the n-th topic level
+	MQTTSource struct {
+		Name     string   `yaml:"name"`
+		Broker   string   `yaml:"broker"`
+		ClientId string   `yaml:"client_id"`
+		Username string   `yaml:"username"`
+		Password string   `yaml:"password"`
+		Topics   []string `yaml:"topics"`
+		QoS      byte     `yaml:"qos"`
+		// the common update interval is used if zero
+		UpdateInterval time.Duration `yaml:"update_interval"`
+		StaleAfter     time.Duration `yaml:"stale_after"`
+		MinBackoff     time.Duration `yaml:"min_backoff"`
+		MaxBackoff     time.Duration `yaml:"max_backoff"`
+		Template       struct {
+			Id         string            `yaml:"id"`
+			Title      string            `yaml:"title"`
+			Latitude   string            `yaml:"latitude"`
+			Longitude  string            `yaml:"longitude"`
+			ObservedAt string            `yaml:"observed_at"`
+			Readings   map[string]string `yaml:"readings"`
+		} `yaml:"template"`
+	}
+	// Delimited file with a station per row read from Path or downloaded from URL.
+	// Columns are names if the file has a header, otherwise zero based indexes
+	CSVSource struct {
+		Name           string        `yaml:"name"`
+		Path           string        `yaml:"path"`
+		URL            string        `yaml:"url"`
+		UpdateInterval time.Duration `yaml:"update_interval"`
+		// comma if empty, '\t' for TSV
+		Delimiter string `yaml:"delimiter"`
+		Header    bool   `yaml:"header"`
+		Columns   struct {
+			Id        string `yaml:"id"`
+			Title     string `yaml:"title"`
+			Latitude  string `yaml:"latitude"`
+			Longitude string `yaml:"longitude"`
+		} `yaml:"columns"`
+	}
+	// Refuses a refresh deleting more trackers of the source than allowed, zero disables a limit
+	DeletionGuard struct {
+		MaxPercent float64 `yaml:"max_percent"`
+		MaxCount   int     `yaml:"max_count"`
+	}
+	// Suspends fetches of a source for CoolDown after FailureThreshold failures in a row,
+	// zero threshold disables the breaker
+	CircuitBreaker struct {
+		FailureThreshold int           `yaml:"failure_threshold"`
+		CoolDown         time.Duration `yaml:"cool_down"`
+	}
+	// Retries of a failed fetch, zero fields take the defaults of the retry package
+	RetryPolicy struct {
+		MaxAttempts int           `yaml:"max_attempts"`
+		BaseDelay   time.Duration `yaml:"base_delay"`
+		MaxDelay    time.Duration `yaml:"max_delay"`
+	}
+)
+
+const (
+	StorageSqlite   = "sqlite"
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
 )
 
 // gets config path from a command line flag, then from an env variable CONFIG_PATH
@@ -3,15 +3,18 @@ package armaqi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers"
 	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
 )
 
 const (
 	url        = "https://armaqi.org/api/waqi/list"
+	infoUrl    = "https://armaqi.org/api/waqi/info?id=%s"
 	sourceName = "armaqi"
 )
 
@@ -31,6 +34,17 @@ type (
 		Longitude float64 `json:"lng"`
 	}
 
+	InfoResponse struct {
+		Station Station `json:"station"`
+	}
+
+	Station struct {
+		Id          int       `json:"id"`
+		PM25        *float64  `json:"pm25"`
+		PM10        *float64  `json:"pm10"`
+		LastUpdated time.Time `json:"lastUpdated"`
+	}
+
 	Armaqi struct {
 		httpClient     *http.Client
 		name           models.SourceName
@@ -67,6 +81,10 @@ func (a *Armaqi) Fetch(ctx context.Context) ([]models.Tracker, error) {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, fetchers.NewStatusError(resp)
+	}
+
 	var decoded Response
 
 	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
@@ -87,3 +105,74 @@ func (a *Armaqi) Fetch(ctx context.Context) ([]models.Tracker, error) {
 
 	return res, nil
 }
+
+// Requests the info endpoint for every tracker and returns its pollutant readings.
+// Trackers that failed are skipped, their errors are joined into the returned error
+func (a *Armaqi) FetchMeasurements(ctx context.Context, trackers []models.Tracker) ([]models.Measurement, error) {
+	var (
+		res  []models.Measurement
+		errs []error
+	)
+
+	for _, tracker := range trackers {
+		station, err := a.fetchStation(ctx, tracker.OrigId)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("station %s: %w", tracker.OrigId, err))
+			continue
+		}
+
+		if station.LastUpdated.IsZero() {
+			errs = append(errs, fmt.Errorf("station %s: no lastUpdated field", tracker.OrigId))
+			continue
+		}
+
+		readings := []struct {
+			pollutant models.Pollutant
+			value     *float64
+		}{
+			{models.PM25, station.PM25},
+			{models.PM10, station.PM10},
+		}
+
+		for _, r := range readings {
+			if r.value == nil {
+				continue
+			}
+			res = append(res, models.Measurement{
+				TrackerId:  tracker.Id(),
+				Pollutant:  r.pollutant,
+				Value:      *r.value,
+				Unit:       models.UnitMicrogramsPerCubicMeter,
+				ObservedAt: station.LastUpdated,
+			})
+		}
+	}
+
+	return res, errors.Join(errs...)
+}
+
+func (a *Armaqi) fetchStation(ctx context.Context, id string) (*Station, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(infoUrl, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fetchers.NewStatusError(resp)
+	}
+
+	var decoded InfoResponse
+
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	return &decoded.Station, nil
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/armaqi"
 	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
@@ -82,3 +83,71 @@ func TestArmaqi_Fetch(t *testing.T) {
 	require.Equal(t, want, got)
 
 }
+
+func TestArmaqi_FetchMeasurements(t *testing.T) {
+	const respJSON = `{
+		"station": {
+		  "id": 76921,
+		  "title": "Kentron",
+		  "position": {
+			"lat": 40.182,
+			"lng": 44.516
+		  },
+		  "pm25": 15,
+		  "pm10": 10,
+		  "lastUpdated": "2024-03-08T10:37:33Z"
+		}
+	  }`
+
+	trackers := []models.Tracker{
+		{
+			OrigId: "76921",
+			Source: "armaqi",
+		},
+		{
+			OrigId: "397555",
+			Source: "armaqi",
+		},
+	}
+
+	observedAt := time.Date(2024, 3, 8, 10, 37, 33, 0, time.UTC)
+
+	want := []models.Measurement{
+		{
+			TrackerId:  "armaqi|76921",
+			Pollutant:  models.PM25,
+			Value:      15,
+			Unit:       models.UnitMicrogramsPerCubicMeter,
+			ObservedAt: observedAt,
+		},
+		{
+			TrackerId:  "armaqi|76921",
+			Pollutant:  models.PM10,
+			Value:      10,
+			Unit:       models.UnitMicrogramsPerCubicMeter,
+			ObservedAt: observedAt,
+		},
+	}
+
+	testClient := NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.String() == "https://armaqi.org/api/waqi/info?id=76921" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(respJSON)),
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Status:     "404 Not Found",
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader("")),
+		}
+	})
+
+	armaqi := armaqi.New(testClient, 1)
+
+	got, err := armaqi.FetchMeasurements(context.Background(), trackers)
+	require.ErrorContains(t, err, "station 397555")
+	require.Equal(t, want, got)
+}
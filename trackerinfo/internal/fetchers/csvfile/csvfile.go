@@ -0,0 +1,388 @@
+package csvfile
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+)
+
+type (
+	// Describes a delimited file with a station per row. Either Path or URL is set
+	Config struct {
+		Name           string
+		Path           string
+		URL            string
+		UpdateInterval time.Duration
+		// a single character, comma if empty
+		Delimiter string
+		// the first row holds column names
+		Header bool
+		// column names if the file has a header, otherwise zero based indexes
+		Columns Columns
+	}
+
+	Columns struct {
+		Id        string
+		Title     string
+		Latitude  string
+		Longitude string
+	}
+
+	CSVFile struct {
+		log        *slog.Logger
+		httpClient *http.Client
+		cfg        Config
+		delimiter  rune
+
+		// the last parsed file, it isn't parsed again until it changes
+		mu       sync.Mutex
+		version  version
+		sum      [sha256.Size]byte
+		trackers []models.Tracker
+	}
+
+	// modTime holds Last-Modified of a downloaded file
+	version struct {
+		modTime time.Time
+		size    int64
+		etag    string
+	}
+
+	// column indexes of the mapped fields, title is -1 if not mapped
+	layout struct {
+		id, title, latitude, longitude int
+	}
+)
+
+func New(log *slog.Logger, client *http.Client, cfg Config) (*CSVFile, error) {
+	const op = "csvfile.New"
+
+	if len(cfg.Name) == 0 {
+		return nil, fmt.Errorf("%s: name is required", op)
+	}
+	if (len(cfg.Path) == 0) == (len(cfg.URL) == 0) {
+		return nil, fmt.Errorf("%s: %s: either path or url is required", op, cfg.Name)
+	}
+	if len(cfg.Columns.Id) == 0 || len(cfg.Columns.Latitude) == 0 || len(cfg.Columns.Longitude) == 0 {
+		return nil, fmt.Errorf("%s: %s: id, latitude and longitude columns are required", op, cfg.Name)
+	}
+
+	delimiter := ','
+	if len(cfg.Delimiter) != 0 {
+		// yaml keeps "\t" escaped in single quoted strings
+		d := strings.ReplaceAll(cfg.Delimiter, `\t`, "\t")
+		r, size := utf8.DecodeRuneInString(d)
+		if size != len(d) || r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+			return nil, fmt.Errorf("%s: %s: bad delimiter %q", op, cfg.Name, cfg.Delimiter)
+		}
+		delimiter = r
+	}
+
+	if !cfg.Header {
+		for _, c := range []string{cfg.Columns.Id, cfg.Columns.Title, cfg.Columns.Latitude, cfg.Columns.Longitude} {
+			if _, err := columnIndex(c); len(c) != 0 && err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", op, cfg.Name, err)
+			}
+		}
+	}
+
+	return &CSVFile{
+		log:        log.With(slog.String("source", cfg.Name)),
+		httpClient: client,
+		cfg:        cfg,
+		delimiter:  delimiter,
+	}, nil
+}
+
+func (c *CSVFile) Name() models.SourceName {
+	return models.SourceName(c.cfg.Name)
+}
+
+func (c *CSVFile) UpdateInterval() time.Duration {
+	return c.cfg.UpdateInterval
+}
+
+// Reads the file if it has changed since the last fetch, otherwise returns the trackers read before.
+// Bad rows are logged with their line numbers and skipped
+func (c *CSVFile) Fetch(ctx context.Context) ([]models.Tracker, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var (
+		data []byte
+		ver  *version
+		err  error
+	)
+	if len(c.cfg.Path) != 0 {
+		data, ver, err = c.readFile()
+	} else {
+		data, ver, err = c.download(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ver == nil {
+		return c.trackers, nil
+	}
+
+	// a touched file with the same content isn't parsed again
+	if sum := sha256.Sum256(data); sum != c.sum || c.trackers == nil {
+		trackers, err := c.parse(data)
+		if err != nil {
+			return nil, err
+		}
+		c.sum = sum
+		c.trackers = trackers
+	}
+	// a file failed to parse is read again on the next fetch
+	c.version = *ver
+
+	return c.trackers, nil
+}
+
+// Returns a nil version if the file hasn't changed since the last parse
+func (c *CSVFile) readFile() ([]byte, *version, error) {
+	info, err := os.Stat(c.cfg.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if c.trackers != nil && info.ModTime().Equal(c.version.modTime) && info.Size() == c.version.size {
+		return nil, nil, nil
+	}
+
+	data, err := os.ReadFile(c.cfg.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, &version{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// Returns a nil version if the server reports the file not modified since the last parse
+func (c *CSVFile) download(ctx context.Context) ([]byte, *version, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.URL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.trackers != nil {
+		if len(c.version.etag) != 0 {
+			req.Header.Set("If-None-Match", c.version.etag)
+		}
+		if !c.version.modTime.IsZero() {
+			req.Header.Set("If-Modified-Since", c.version.modTime.UTC().Format(http.TimeFormat))
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && c.trackers != nil {
+		return nil, nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fetchers.NewStatusError(resp)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ver := &version{etag: resp.Header.Get("ETag")}
+	ver.modTime, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+
+	return data, ver, nil
+}
+
+func (c *CSVFile) parse(data []byte) ([]models.Tracker, error) {
+	const op = "csvfile.parse"
+	log := c.log.With(slog.String("op", op))
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = c.delimiter
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	var cols layout
+	if c.cfg.Header {
+		header, err := r.Read()
+		if err != nil {
+			return nil, fmt.Errorf("header: %w", err)
+		}
+		if cols, err = c.headerLayout(header); err != nil {
+			return nil, err
+		}
+	} else {
+		cols = c.indexLayout()
+	}
+
+	var res []models.Tracker
+	// line of the first row of each id
+	seen := make(map[string]int)
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			log.Warn("bad row skipped", slog.Int("line", parseErr.StartLine), slog.String("error", parseErr.Err.Error()))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := r.FieldPos(0)
+
+		tr, err := c.tracker(record, cols)
+		if err != nil {
+			log.Warn("bad row skipped", slog.Int("line", line), slog.String("error", err.Error()))
+			continue
+		}
+		if first, dup := seen[tr.OrigId]; dup {
+			log.Warn("bad row skipped", slog.Int("line", line),
+				slog.String("error", fmt.Sprintf("duplicate id %s, first at line %d", tr.OrigId, first)))
+			continue
+		}
+		seen[tr.OrigId] = line
+		res = append(res, tr)
+	}
+
+	// an unreadable file must not remove the trackers of the source
+	if len(res) == 0 {
+		return nil, errors.New("no valid rows")
+	}
+
+	return res, nil
+}
+
+func (c *CSVFile) headerLayout(header []string) (layout, error) {
+	find := func(name string) (int, error) {
+		if len(name) == 0 {
+			return -1, nil
+		}
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), name) {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("header: no column %q", name)
+	}
+
+	var (
+		l   layout
+		err error
+	)
+	if l.id, err = find(c.cfg.Columns.Id); err != nil {
+		return l, err
+	}
+	if l.title, err = find(c.cfg.Columns.Title); err != nil {
+		return l, err
+	}
+	if l.latitude, err = find(c.cfg.Columns.Latitude); err != nil {
+		return l, err
+	}
+	if l.longitude, err = find(c.cfg.Columns.Longitude); err != nil {
+		return l, err
+	}
+	return l, nil
+}
+
+// Columns are validated by New
+func (c *CSVFile) indexLayout() layout {
+	index := func(col string) int {
+		if len(col) == 0 {
+			return -1
+		}
+		i, _ := columnIndex(col)
+		return i
+	}
+
+	return layout{
+		id:        index(c.cfg.Columns.Id),
+		title:     index(c.cfg.Columns.Title),
+		latitude:  index(c.cfg.Columns.Latitude),
+		longitude: index(c.cfg.Columns.Longitude),
+	}
+}
+
+func (c *CSVFile) tracker(record []string, cols layout) (models.Tracker, error) {
+	field := func(i int) (string, error) {
+		if i >= len(record) {
+			return "", fmt.Errorf("%d columns, column %d expected", len(record), i+1)
+		}
+		return strings.TrimSpace(record[i]), nil
+	}
+
+	id, err := field(cols.id)
+	if err != nil {
+		return models.Tracker{}, err
+	}
+	if len(id) == 0 {
+		return models.Tracker{}, errors.New("id is empty")
+	}
+
+	var title string
+	if cols.title >= 0 {
+		if title, err = field(cols.title); err != nil {
+			return models.Tracker{}, err
+		}
+	}
+
+	number := func(i int) (float64, error) {
+		s, err := field(i)
+		if err != nil {
+			return 0, err
+		}
+		return strconv.ParseFloat(s, 64)
+	}
+
+	lat, err := number(cols.latitude)
+	if err != nil {
+		return models.Tracker{}, fmt.Errorf("latitude: %w", err)
+	}
+	lng, err := number(cols.longitude)
+	if err != nil {
+		return models.Tracker{}, fmt.Errorf("longitude: %w", err)
+	}
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return models.Tracker{}, fmt.Errorf("position %f,%f is out of range", lat, lng)
+	}
+
+	return models.Tracker{
+		OrigId:      id,
+		Source:      c.cfg.Name,
+		Description: title,
+		Latitude:    lat,
+		Longitude:   lng,
+	}, nil
+}
+
+func columnIndex(col string) (int, error) {
+	i, err := strconv.Atoi(col)
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("column %q must be an index when the file has no header", col)
+	}
+	return i, nil
+}
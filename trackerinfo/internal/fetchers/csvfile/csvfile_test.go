@@ -0,0 +1,175 @@
+package csvfile_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MRibalko/smogtracker/pkg/logger/slogdiscard"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/csvfile"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type RoundTripFunc func(req *http.Request) *http.Response
+
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
+
+func TestCSVFile_FetchFile(t *testing.T) {
+	const data = "Station ID;Name;Lat;Lng\n" +
+		"76921;Kentron;40.182;44.516\n" +
+		"3;Broken;north;44.5\n" +
+		"397555;Nor Nork;40.2;44.582\n" +
+		"4;Short\n" +
+		"76921;Kentron again;40.1;44.5\n"
+
+	path := filepath.Join(t.TempDir(), "stations.csv")
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+
+	var logs bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&logs, nil))
+
+	fetcher, err := csvfile.New(log, http.DefaultClient, csvfile.Config{
+		Name:      "partner",
+		Path:      path,
+		Delimiter: ";",
+		Header:    true,
+		Columns: csvfile.Columns{
+			Id:        "station id",
+			Title:     "name",
+			Latitude:  "lat",
+			Longitude: "lng",
+		},
+	})
+	require.NoError(t, err)
+
+	want := []models.Tracker{
+		{OrigId: "76921", Source: "partner", Description: "Kentron", Latitude: 40.182, Longitude: 44.516},
+		{OrigId: "397555", Source: "partner", Description: "Nor Nork", Latitude: 40.2, Longitude: 44.582},
+	}
+
+	got, err := fetcher.Fetch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	assert.Contains(t, logs.String(), "line=3")
+	assert.Contains(t, logs.String(), "line=5")
+	assert.Contains(t, logs.String(), "line=6", "repeated id is a bad row")
+
+	t.Run("Unchanged file isn't parsed", func(t *testing.T) {
+		logs.Reset()
+
+		got, err := fetcher.Fetch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Empty(t, logs.String(), "bad rows are reported once")
+	})
+
+	t.Run("Changed file", func(t *testing.T) {
+		changed := "Station ID;Name;Lat;Lng\n76921;Kentron;40.183;44.516\n"
+		require.NoError(t, os.WriteFile(path, []byte(changed), 0o644))
+		// the size differs anyway, the time is moved in case of a coarse file system clock
+		later := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(path, later, later))
+
+		got, err := fetcher.Fetch(context.Background())
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, 40.183, got[0].Latitude)
+	})
+
+	t.Run("File without valid rows", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("Station ID;Name;Lat;Lng\n"), 0o644))
+
+		_, err := fetcher.Fetch(context.Background())
+		assert.Error(t, err)
+
+		_, err = fetcher.Fetch(context.Background())
+		assert.Error(t, err, "the file is read again until it is fixed")
+	})
+}
+
+func TestCSVFile_FetchURL(t *testing.T) {
+	const (
+		url  = "https://example.org/stations.tsv"
+		data = "76921\tKentron\t40.182\t44.516\n397555\tNor Nork\t40.2\t44.582\n"
+		etag = `"v1"`
+	)
+
+	requests := 0
+	client := &http.Client{Transport: RoundTripFunc(func(req *http.Request) *http.Response {
+		requests++
+		assert.Equal(t, url, req.URL.String())
+
+		if req.Header.Get("If-None-Match") == etag {
+			return &http.Response{
+				StatusCode: http.StatusNotModified,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader("")),
+			}
+		}
+
+		header := make(http.Header)
+		header.Set("ETag", etag)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       io.NopCloser(strings.NewReader(data)),
+		}
+	})}
+
+	fetcher, err := csvfile.New(slogdiscard.NewDiscardLogger(), client, csvfile.Config{
+		Name:      "partner",
+		URL:       url,
+		Delimiter: `\t`,
+		Columns: csvfile.Columns{
+			Id:        "0",
+			Title:     "1",
+			Latitude:  "2",
+			Longitude: "3",
+		},
+	})
+	require.NoError(t, err)
+
+	first, err := fetcher.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	assert.Equal(t, "Nor Nork", first[1].Description)
+
+	second, err := fetcher.Fetch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, first, second, "not modified file is served from the cache")
+	assert.Equal(t, 2, requests)
+}
+
+func TestCSVFile_New(t *testing.T) {
+	columns := csvfile.Columns{Id: "id", Latitude: "lat", Longitude: "lng"}
+
+	cases := []struct {
+		name string
+		cfg  csvfile.Config
+	}{
+		{"no location", csvfile.Config{Name: "partner", Header: true, Columns: columns}},
+		{"path and url", csvfile.Config{Name: "partner", Path: "a.csv", URL: "https://example.org/a.csv", Header: true, Columns: columns}},
+		{"names without header", csvfile.Config{Name: "partner", Path: "a.csv", Columns: columns}},
+		{"long delimiter", csvfile.Config{Name: "partner", Path: "a.csv", Delimiter: ";;", Header: true, Columns: columns}},
+		{"no id column", csvfile.Config{Name: "partner", Path: "a.csv", Header: true, Columns: csvfile.Columns{Latitude: "lat", Longitude: "lng"}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := csvfile.New(slogdiscard.NewDiscardLogger(), http.DefaultClient, tc.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
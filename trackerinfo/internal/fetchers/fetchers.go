@@ -0,0 +1,41 @@
+package fetchers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Response of a source with a status other than the expected one
+type StatusError struct {
+	StatusCode int
+	Status     string
+	// zero unless the response had a Retry-After header
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %s", e.Status)
+}
+
+// Reads the status and the Retry-After header given in seconds or as an http date
+func NewStatusError(resp *http.Response) *StatusError {
+	e := &StatusError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+
+	if len(e.Status) == 0 {
+		e.Status = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	retryAfter := resp.Header.Get("Retry-After")
+	if secs, err := strconv.Atoi(retryAfter); err == nil && secs > 0 {
+		e.RetryAfter = time.Duration(secs) * time.Second
+	} else if at, err := http.ParseTime(retryAfter); err == nil {
+		e.RetryAfter = max(time.Until(at), 0)
+	}
+
+	return e
+}
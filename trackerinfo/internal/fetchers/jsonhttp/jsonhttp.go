@@ -0,0 +1,222 @@
+package jsonhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	sl "github.com/MRibalko/smogtracker/pkg/logger"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/jsonpath"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+)
+
+type (
+	// Describes a source publishing its stations as a JSON document
+	Config struct {
+		Name string
+		URL  string
+		// GET if empty
+		Method         string
+		Headers        map[string]string
+		Body           string
+		UpdateInterval time.Duration
+		// path to the station array, or to the stations themselves if it has a wildcard
+		Items string
+		// paths relative to a station
+		Fields Fields
+	}
+
+	Fields struct {
+		Id        string
+		Title     string
+		Latitude  string
+		Longitude string
+	}
+
+	JSONHTTP struct {
+		log        *slog.Logger
+		httpClient *http.Client
+		cfg        Config
+		items      jsonpath.Path
+		id         jsonpath.Path
+		title      jsonpath.Path
+		latitude   jsonpath.Path
+		longitude  jsonpath.Path
+	}
+)
+
+// Validates the config and compiles its paths
+func New(log *slog.Logger, client *http.Client, cfg Config) (*JSONHTTP, error) {
+	const op = "jsonhttp.New"
+
+	if len(cfg.Name) == 0 || len(cfg.URL) == 0 {
+		return nil, fmt.Errorf("%s: name and url are required", op)
+	}
+	if len(cfg.Method) == 0 {
+		cfg.Method = http.MethodGet
+	}
+
+	j := &JSONHTTP{
+		log:        log.With(slog.String("source", cfg.Name)),
+		httpClient: client,
+		cfg:        cfg,
+	}
+
+	paths := []struct {
+		name     string
+		expr     string
+		required bool
+		dst      *jsonpath.Path
+	}{
+		{"items", cfg.Items, false, &j.items},
+		{"id", cfg.Fields.Id, true, &j.id},
+		{"title", cfg.Fields.Title, false, &j.title},
+		{"latitude", cfg.Fields.Latitude, true, &j.latitude},
+		{"longitude", cfg.Fields.Longitude, true, &j.longitude},
+	}
+
+	for _, p := range paths {
+		if p.required && len(p.expr) == 0 {
+			return nil, fmt.Errorf("%s: %s: field %s is required", op, cfg.Name, p.name)
+		}
+		path, err := jsonpath.Parse(p.expr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", op, cfg.Name, err)
+		}
+		*p.dst = path
+	}
+
+	return j, nil
+}
+
+func (j *JSONHTTP) Name() models.SourceName {
+	return models.SourceName(j.cfg.Name)
+}
+
+func (j *JSONHTTP) UpdateInterval() time.Duration {
+	return j.cfg.UpdateInterval
+}
+
+// Requests the document and maps its stations to trackers.
+// Stations which can't be mapped and repeated ids are logged and skipped
+func (j *JSONHTTP) Fetch(ctx context.Context) ([]models.Tracker, error) {
+	const op = "jsonhttp.Fetch"
+	log := j.log.With(slog.String("op", op))
+
+	doc, err := j.fetchDocument(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var stations []any
+	for _, v := range j.items.Eval(doc) {
+		if arr, ok := v.([]any); ok {
+			stations = append(stations, arr...)
+			continue
+		}
+		stations = append(stations, v)
+	}
+
+	if len(stations) == 0 {
+		return nil, fmt.Errorf("no stations at %q", j.cfg.Items)
+	}
+
+	res := make([]models.Tracker, 0, len(stations))
+	seen := make(map[string]struct{}, len(stations))
+	for i, station := range stations {
+		tr, err := j.tracker(station)
+		if err != nil {
+			log.Warn("station skipped", slog.Int("index", i), sl.Err(err))
+			continue
+		}
+		if _, dup := seen[tr.OrigId]; dup {
+			log.Warn("station skipped", slog.Int("index", i), slog.String("error", "duplicate id "+tr.OrigId))
+			continue
+		}
+		seen[tr.OrigId] = struct{}{}
+		res = append(res, tr)
+	}
+
+	return res, nil
+}
+
+func (j *JSONHTTP) fetchDocument(ctx context.Context) (any, error) {
+	var body io.Reader
+	if len(j.cfg.Body) != 0 {
+		body = strings.NewReader(j.cfg.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, j.cfg.Method, j.cfg.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range j.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := j.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fetchers.NewStatusError(resp)
+	}
+
+	// numbers are kept as written, so ids don't turn into floats
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func (j *JSONHTTP) tracker(station any) (models.Tracker, error) {
+	id, err := j.id.Text(station)
+	if err != nil {
+		return models.Tracker{}, fmt.Errorf("id: %w", err)
+	}
+	if len(id) == 0 {
+		return models.Tracker{}, errors.New("id: empty")
+	}
+
+	var title string
+	if len(j.title) != 0 {
+		if title, err = j.title.Text(station); err != nil {
+			return models.Tracker{}, fmt.Errorf("title: %w", err)
+		}
+	}
+
+	lat, err := j.latitude.Number(station)
+	if err != nil {
+		return models.Tracker{}, fmt.Errorf("latitude: %w", err)
+	}
+	lng, err := j.longitude.Number(station)
+	if err != nil {
+		return models.Tracker{}, fmt.Errorf("longitude: %w", err)
+	}
+
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return models.Tracker{}, fmt.Errorf("position %f,%f is out of range", lat, lng)
+	}
+
+	return models.Tracker{
+		OrigId:      id,
+		Source:      j.cfg.Name,
+		Description: title,
+		Latitude:    lat,
+		Longitude:   lng,
+	}, nil
+}
@@ -0,0 +1,181 @@
+package jsonhttp_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MRibalko/smogtracker/pkg/logger/slogdiscard"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/jsonhttp"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type RoundTripFunc func(req *http.Request) *http.Response
+
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
+
+func NewTestClient(f RoundTripFunc) *http.Client {
+	return &http.Client{
+		Transport: RoundTripFunc(f),
+	}
+}
+
+func TestJSONHTTP_Fetch(t *testing.T) {
+	const (
+		url      = "https://example.org/api/stations"
+		respJSON = `{
+		"data": {
+			"stations": [
+				{"id": 76921, "name": "Kentron", "coordinates": [40.182, 44.516]},
+				{"id": "397555", "name": "Nor Nork", "coordinates": ["40.2", "44.582"]},
+				{"id": 3, "name": "No position"},
+				{"id": 4, "name": "Out of range", "coordinates": [140, 44.582]},
+				{"id": "76921", "name": "Repeated", "coordinates": [40.1, 44.5]}
+			]
+		}
+	}`
+	)
+
+	cfg := jsonhttp.Config{
+		Name:           "example",
+		URL:            url,
+		Method:         http.MethodPost,
+		Headers:        map[string]string{"X-Api-Key": "secret"},
+		Body:           `{"region": "am"}`,
+		UpdateInterval: time.Minute,
+		Items:          "$.data.stations",
+		Fields: jsonhttp.Fields{
+			Id:        "id",
+			Title:     "name",
+			Latitude:  "coordinates[0]",
+			Longitude: "coordinates[1]",
+		},
+	}
+
+	testClient := NewTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, url, req.URL.String())
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "secret", req.Header.Get("X-Api-Key"))
+
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, cfg.Body, string(body))
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(respJSON)),
+		}
+	})
+
+	fetcher, err := jsonhttp.New(slogdiscard.NewDiscardLogger(), testClient, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, models.SourceName("example"), fetcher.Name())
+	assert.Equal(t, time.Minute, fetcher.UpdateInterval())
+
+	got, err := fetcher.Fetch(context.Background())
+	require.NoError(t, err)
+
+	want := []models.Tracker{
+		{
+			OrigId:      "76921",
+			Source:      "example",
+			Description: "Kentron",
+			Latitude:    40.182,
+			Longitude:   44.516,
+		},
+		{
+			OrigId:      "397555",
+			Source:      "example",
+			Description: "Nor Nork",
+			Latitude:    40.2,
+			Longitude:   44.582,
+		},
+	}
+
+	assert.Equal(t, want, got, "stations without a valid position and repeated ids are skipped")
+}
+
+func TestJSONHTTP_FetchErrors(t *testing.T) {
+	cfg := jsonhttp.Config{
+		Name:  "example",
+		URL:   "https://example.org/api/stations",
+		Items: "stations",
+		Fields: jsonhttp.Fields{
+			Id:        "id",
+			Latitude:  "lat",
+			Longitude: "lng",
+		},
+	}
+
+	cases := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"unexpected status", http.StatusBadGateway, `{}`},
+		{"malformed document", http.StatusOK, `{"stations": [`},
+		{"no stations", http.StatusOK, `{"items": []}`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			testClient := NewTestClient(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: tc.status,
+					Status:     http.StatusText(tc.status),
+					Header:     make(http.Header),
+					Body:       io.NopCloser(strings.NewReader(tc.body)),
+				}
+			})
+
+			fetcher, err := jsonhttp.New(slogdiscard.NewDiscardLogger(), testClient, cfg)
+			require.NoError(t, err)
+
+			_, err = fetcher.Fetch(context.Background())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestJSONHTTP_New(t *testing.T) {
+	valid := jsonhttp.Config{
+		Name: "example",
+		URL:  "https://example.org/api/stations",
+		Fields: jsonhttp.Fields{
+			Id:        "id",
+			Latitude:  "lat",
+			Longitude: "lng",
+		},
+	}
+
+	_, err := jsonhttp.New(slogdiscard.NewDiscardLogger(), http.DefaultClient, valid)
+	require.NoError(t, err)
+
+	noName := valid
+	noName.Name = ""
+
+	noId := valid
+	noId.Fields.Id = ""
+
+	badPath := valid
+	badPath.Items = "stations["
+
+	for name, cfg := range map[string]jsonhttp.Config{
+		"no name":  noName,
+		"no id":    noId,
+		"bad path": badPath,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := jsonhttp.New(slogdiscard.NewDiscardLogger(), http.DefaultClient, cfg)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package mqttsource_test
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Minimal MQTT 3.1.1 broker delivering QoS 0 messages published by the test
+type testBroker struct {
+	listener net.Listener
+
+	mu    sync.Mutex
+	conns map[net.Conn][]string
+	// number of SUBSCRIBE packets received
+	subscribes int
+}
+
+func newTestBroker(t *testing.T) *testBroker {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	b := &testBroker{
+		listener: l,
+		conns:    make(map[net.Conn][]string),
+	}
+	go b.serve()
+	t.Cleanup(func() {
+		l.Close()
+		b.dropClients()
+	})
+
+	return b
+}
+
+func (b *testBroker) url() string {
+	return "tcp://" + b.listener.Addr().String()
+}
+
+func (b *testBroker) serve() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		b.conns[conn] = nil
+		b.mu.Unlock()
+
+		go b.handle(conn)
+	}
+}
+
+func (b *testBroker) handle(conn net.Conn) {
+	defer func() {
+		b.mu.Lock()
+		delete(b.conns, conn)
+		b.mu.Unlock()
+		conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	for {
+		header, body, err := readPacket(r)
+		if err != nil {
+			return
+		}
+
+		switch header >> 4 {
+		case 1: // CONNECT
+			b.write(conn, []byte{0x20, 0x02, 0x00, 0x00})
+		case 8: // SUBSCRIBE
+			var filters []string
+			granted := []byte{}
+			for rest := body[2:]; len(rest) > 2; {
+				n := int(binary.BigEndian.Uint16(rest))
+				filters = append(filters, string(rest[2:2+n]))
+				granted = append(granted, 0)
+				rest = rest[2+n+1:]
+			}
+			b.mu.Lock()
+			b.conns[conn] = append(b.conns[conn], filters...)
+			b.subscribes++
+			b.mu.Unlock()
+			b.write(conn, append([]byte{0x90, byte(2 + len(granted)), body[0], body[1]}, granted...))
+		case 10: // UNSUBSCRIBE
+			b.write(conn, []byte{0xB0, 0x02, body[0], body[1]})
+		case 12: // PINGREQ
+			b.write(conn, []byte{0xD0, 0x00})
+		case 14: // DISCONNECT
+			return
+		}
+	}
+}
+
+func (b *testBroker) write(conn net.Conn, packet []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, _ = conn.Write(packet)
+}
+
+// Delivers the message to every client subscribed to a matching filter
+func (b *testBroker) publish(topic string, payload []byte) {
+	body := binary.BigEndian.AppendUint16(nil, uint16(len(topic)))
+	body = append(body, topic...)
+	body = append(body, payload...)
+
+	packet := append([]byte{0x30}, remainingLength(len(body))...)
+	packet = append(packet, body...)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for conn, filters := range b.conns {
+		for _, f := range filters {
+			if topicMatches(f, topic) {
+				_, _ = conn.Write(packet)
+				break
+			}
+		}
+	}
+}
+
+func (b *testBroker) subscribeCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.subscribes
+}
+
+// Closes the connections of all clients as a broker restart would
+func (b *testBroker) dropClients() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for conn := range b.conns {
+		conn.Close()
+	}
+}
+
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	length, multiplier := 0, 1
+	for {
+		d, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(d&0x7F) * multiplier
+		if d&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+		if multiplier > 128*128*128 {
+			return 0, nil, errors.New("malformed remaining length")
+		}
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+func remainingLength(n int) []byte {
+	var res []byte
+	for {
+		d := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			d |= 0x80
+		}
+		res = append(res, d)
+		if n == 0 {
+			return res
+		}
+	}
+}
+
+func topicMatches(filter, topic string) bool {
+	f, t := strings.Split(filter, "/"), strings.Split(topic, "/")
+	for i, level := range f {
+		if level == "#" {
+			return true
+		}
+		if i >= len(t) || (level != "+" && level != t[i]) {
+			return false
+		}
+	}
+	return len(f) == len(t)
+}
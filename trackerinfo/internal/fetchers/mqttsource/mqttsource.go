@@ -0,0 +1,250 @@
+package mqttsource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	sl "github.com/MRibalko/smogtracker/pkg/logger"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+const (
+	defaultMinBackoff = time.Second
+	defaultMaxBackoff = 2 * time.Minute
+	// readings kept between two FetchMeasurements calls, the oldest are dropped first
+	maxPendingReadings = 10000
+)
+
+var ErrNotConnected = errors.New("not connected to the broker")
+
+type (
+	Config struct {
+		Name string
+		// like tcp://localhost:1883
+		Broker   string
+		ClientId string
+		Username string
+		Password string
+		// topic filters with + and # wildcards
+		Topics []string
+		QoS    byte
+		// how often TrackerList takes the station set
+		UpdateInterval time.Duration
+		// stations silent for longer are dropped from the set, zero keeps them
+		StaleAfter time.Duration
+		// the first connection is retried every MinBackoff,
+		// a lost connection is retried with delays doubling up to MaxBackoff
+		MinBackoff time.Duration
+		MaxBackoff time.Duration
+		Template   Template
+	}
+
+	// Source fed by MQTT messages. It keeps the set of stations heard from
+	// and returns it to TrackerList like a fetched feed
+	MQTT struct {
+		log      *slog.Logger
+		cfg      Config
+		template *compiled
+		client   mqtt.Client
+		now      func() time.Time
+
+		mu       sync.Mutex
+		stations map[models.Id]station
+		readings []models.Measurement
+	}
+
+	station struct {
+		tracker  models.Tracker
+		lastSeen time.Time
+	}
+)
+
+func New(log *slog.Logger, cfg Config) (*MQTT, error) {
+	const op = "mqttsource.New"
+
+	if len(cfg.Name) == 0 || len(cfg.Broker) == 0 || len(cfg.Topics) == 0 {
+		return nil, fmt.Errorf("%s: name, broker and topics are required", op)
+	}
+	if cfg.QoS > 2 {
+		return nil, fmt.Errorf("%s: %s: qos must be 0, 1 or 2", op, cfg.Name)
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = defaultMinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = max(defaultMaxBackoff, cfg.MinBackoff)
+	}
+	if len(cfg.ClientId) == 0 {
+		cfg.ClientId = fmt.Sprintf("trackerinfo-%s-%d", cfg.Name, time.Now().UnixNano())
+	}
+
+	template, err := cfg.Template.compile(cfg.Name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s: %w", op, cfg.Name, err)
+	}
+
+	m := &MQTT{
+		log:      log.With(slog.String("source", cfg.Name)),
+		cfg:      cfg,
+		template: template,
+		now:      time.Now,
+		stations: make(map[models.Id]station),
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(cfg.Broker).
+		SetClientID(cfg.ClientId).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetCleanSession(true).
+		SetOrderMatters(false).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(cfg.MinBackoff).
+		SetAutoReconnect(true).
+		SetMaxReconnectInterval(cfg.MaxBackoff).
+		SetOnConnectHandler(m.onConnect).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			m.log.Warn("connection lost, reconnecting", sl.Err(err))
+		})
+
+	m.client = mqtt.NewClient(opts)
+
+	return m, nil
+}
+
+func (m *MQTT) Name() models.SourceName {
+	return models.SourceName(m.cfg.Name)
+}
+
+func (m *MQTT) UpdateInterval() time.Duration {
+	return m.cfg.UpdateInterval
+}
+
+// All the stations going silent is a valid state of the source
+func (m *MQTT) AllowsEmptyFeed() bool {
+	return true
+}
+
+// Starts connecting to the broker in background, the connection is retried until Stop
+func (m *MQTT) Start() error {
+	m.log.Info("connecting to the broker", slog.String("broker", m.cfg.Broker))
+	// with ConnectRetry the token completes only once connected, so it isn't waited for
+	m.client.Connect()
+	return nil
+}
+
+func (m *MQTT) Stop() {
+	m.client.Disconnect(250)
+	m.log.Info("disconnected from the broker")
+}
+
+// The session is clean, so the topics are subscribed on every connection
+func (m *MQTT) onConnect(client mqtt.Client) {
+	const op = "mqttsource.onConnect"
+	log := m.log.With(slog.String("op", op))
+
+	filters := make(map[string]byte, len(m.cfg.Topics))
+	for _, t := range m.cfg.Topics {
+		filters[t] = m.cfg.QoS
+	}
+
+	token := client.SubscribeMultiple(filters, func(_ mqtt.Client, msg mqtt.Message) {
+		m.receive(msg.Topic(), msg.Payload())
+	})
+	go func() {
+		if token.Wait() && token.Error() != nil {
+			log.Error("subscription failed", sl.Err(token.Error()))
+			return
+		}
+		log.Info("subscribed", slog.Any("topics", m.cfg.Topics))
+	}()
+}
+
+func (m *MQTT) receive(topic string, payload []byte) {
+	const op = "mqttsource.receive"
+
+	now := m.now()
+
+	msg, err := m.template.parse(topic, payload, now)
+	if err != nil {
+		m.log.Debug("message skipped", slog.String("op", op), slog.String("topic", topic), sl.Err(err))
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := msg.Tracker.Id()
+	st, known := m.stations[id]
+
+	switch {
+	case msg.HasPosition:
+		st.tracker = msg.Tracker
+	case known:
+		// readings only message keeps the known position
+		if len(msg.Tracker.Description) != 0 {
+			st.tracker.Description = msg.Tracker.Description
+		}
+	default:
+		m.log.Debug("message of an unknown station without position skipped",
+			slog.String("op", op), slog.String("topic", topic))
+		return
+	}
+	st.lastSeen = now
+	m.stations[id] = st
+
+	m.readings = append(m.readings, msg.Readings...)
+	if over := len(m.readings) - maxPendingReadings; over > 0 {
+		m.readings = m.readings[over:]
+	}
+}
+
+// Returns the stations heard from ordered by id, stale ones are dropped.
+// The set is empty, not an error, for a quiet broker, so the stale stations are deleted.
+// Returns ErrNotConnected while the connection is down
+func (m *MQTT) Fetch(ctx context.Context) ([]models.Tracker, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cfg.StaleAfter > 0 {
+		deadline := m.now().Add(-m.cfg.StaleAfter)
+		for id, st := range m.stations {
+			if st.lastSeen.Before(deadline) {
+				delete(m.stations, id)
+			}
+		}
+	}
+
+	// the client state, unlike the connection handlers run in background,
+	// can't be behind a quick reconnect
+	if !m.client.IsConnectionOpen() {
+		return nil, ErrNotConnected
+	}
+
+	res := make([]models.Tracker, 0, len(m.stations))
+	for _, st := range m.stations {
+		res = append(res, st.tracker)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].OrigId < res[j].OrigId
+	})
+
+	return res, nil
+}
+
+// Returns the readings received since the previous call
+func (m *MQTT) FetchMeasurements(ctx context.Context, trackers []models.Tracker) ([]models.Measurement, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res := m.readings
+	m.readings = nil
+
+	return res, nil
+}
@@ -0,0 +1,242 @@
+package mqttsource_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/MRibalko/smogtracker/pkg/logger/slogdiscard"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/mqttsource"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var template = mqttsource.Template{
+	Id:         "topic[1]",
+	Title:      "name",
+	Latitude:   "gps.lat",
+	Longitude:  "gps.lon",
+	ObservedAt: "ts",
+	Readings: map[string]string{
+		"pm25": "pm2_5",
+		"pm10": "pm10",
+	},
+}
+
+// Runs against the broker from MQTT_TEST_BROKER, like tcp://localhost:1883 of a local Mosquitto,
+// or against the embedded one
+func newBroker(t *testing.T) (url string, publish func(topic, payload string)) {
+	t.Helper()
+
+	url = os.Getenv("MQTT_TEST_BROKER")
+	if len(url) == 0 {
+		b := newTestBroker(t)
+		return b.url(), func(topic, payload string) {
+			b.publish(topic, []byte(payload))
+		}
+	}
+
+	client := mqtt.NewClient(mqtt.NewClientOptions().AddBroker(url).SetClientID("trackerinfo-test-publisher"))
+	token := client.Connect()
+	require.True(t, token.WaitTimeout(5*time.Second))
+	require.NoError(t, token.Error())
+	t.Cleanup(func() { client.Disconnect(100) })
+
+	return url, func(topic, payload string) {
+		token := client.Publish(topic, 0, false, payload)
+		token.Wait()
+		assert.NoError(t, token.Error())
+	}
+}
+
+func newSource(t *testing.T, url string, opts ...func(cfg *mqttsource.Config)) *mqttsource.MQTT {
+	t.Helper()
+
+	cfg := mqttsource.Config{
+		Name:           "diy",
+		Broker:         url,
+		Topics:         []string{"smogtracker-test/+/state"},
+		UpdateInterval: time.Minute,
+		MinBackoff:     50 * time.Millisecond,
+		MaxBackoff:     200 * time.Millisecond,
+		Template:       template,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	source, err := mqttsource.New(slogdiscard.NewDiscardLogger(), cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, source.Start())
+	t.Cleanup(source.Stop)
+
+	return source
+}
+
+// Publishes the message until the source has a station, the subscription is made in background
+func publishUntilHeard(t *testing.T, source *mqttsource.MQTT, publish func(topic, payload string), topic, payload string) []models.Tracker {
+	t.Helper()
+
+	var trackers []models.Tracker
+	require.Eventually(t, func() bool {
+		publish(topic, payload)
+		time.Sleep(20 * time.Millisecond)
+
+		var err error
+		trackers, err = source.Fetch(context.Background())
+		return err == nil && len(trackers) != 0
+	}, 5*time.Second, 50*time.Millisecond)
+
+	return trackers
+}
+
+func TestMQTT_Messages(t *testing.T) {
+	ctx := context.Background()
+	url, publish := newBroker(t)
+	source := newSource(t, url)
+
+	trackers := publishUntilHeard(t, source, publish, "smogtracker-test/76921/state",
+		`{"name": "Kentron", "gps": {"lat": 40.182, "lon": 44.516}, "ts": 1709894253, "pm2_5": 15, "pm10": 10}`)
+
+	assert.Equal(t, []models.Tracker{{
+		OrigId:      "76921",
+		Source:      "diy",
+		Description: "Kentron",
+		Latitude:    40.182,
+		Longitude:   44.516,
+	}}, trackers)
+
+	// a readings only message, a message of an unknown station without position and a malformed one
+	publish("smogtracker-test/76921/state", `{"ts": "2024-03-08T10:47:33Z", "pm2_5": 17}`)
+	publish("smogtracker-test/397555/state", `{"pm2_5": 9}`)
+	publish("smogtracker-test/76921/state", `{"pm2_5": `)
+
+	observedAt := time.Date(2024, 3, 8, 10, 37, 33, 0, time.UTC)
+	want := []models.Measurement{
+		{TrackerId: "diy|76921", Pollutant: models.PM10, Value: 10, Unit: models.UnitMicrogramsPerCubicMeter, ObservedAt: observedAt},
+		{TrackerId: "diy|76921", Pollutant: models.PM25, Value: 15, Unit: models.UnitMicrogramsPerCubicMeter, ObservedAt: observedAt},
+		{TrackerId: "diy|76921", Pollutant: models.PM25, Value: 17, Unit: models.UnitMicrogramsPerCubicMeter, ObservedAt: observedAt.Add(10 * time.Minute)},
+	}
+
+	var got []models.Measurement
+	require.Eventually(t, func() bool {
+		m, err := source.FetchMeasurements(ctx, nil)
+		require.NoError(t, err)
+		got = append(got, m...)
+		return len(got) >= len(want)
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// the readings published until the station was heard are duplicates
+	assert.Subset(t, got, want)
+	assert.Equal(t, want[2], got[len(got)-1])
+
+	trackers, err := source.Fetch(ctx)
+	require.NoError(t, err)
+	require.Len(t, trackers, 1, "the station without position isn't added")
+	assert.Equal(t, 40.182, trackers[0].Latitude, "the position is kept")
+}
+
+func TestMQTT_NotConnected(t *testing.T) {
+	// never started, so never connected
+	source, err := mqttsource.New(slogdiscard.NewDiscardLogger(), mqttsource.Config{
+		Name:     "diy",
+		Broker:   "tcp://127.0.0.1:1",
+		Topics:   []string{"smogtracker-test/+/state"},
+		Template: template,
+	})
+	require.NoError(t, err)
+
+	_, err = source.Fetch(context.Background())
+	require.ErrorIs(t, err, mqttsource.ErrNotConnected)
+}
+
+func TestMQTT_Stale(t *testing.T) {
+	url, publish := newBroker(t)
+	source := newSource(t, url, func(cfg *mqttsource.Config) {
+		cfg.StaleAfter = 200 * time.Millisecond
+	})
+
+	publishUntilHeard(t, source, publish, "smogtracker-test/1/state",
+		`{"name": "1", "gps": {"lat": 40.1, "lon": 44.5}}`)
+
+	// all the stations went silent, the empty set deletes their trackers
+	require.Eventually(t, func() bool {
+		trackers, err := source.Fetch(context.Background())
+		require.NoError(t, err)
+		return len(trackers) == 0
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.True(t, source.AllowsEmptyFeed())
+
+	source.Stop()
+	_, err := source.Fetch(context.Background())
+	require.ErrorIs(t, err, mqttsource.ErrNotConnected, "the set isn't trusted without the connection")
+}
+
+func TestMQTT_Reconnect(t *testing.T) {
+	b := newTestBroker(t)
+	publish := func(topic, payload string) {
+		b.publish(topic, []byte(payload))
+	}
+	source := newSource(t, b.url())
+
+	publishUntilHeard(t, source, publish, "smogtracker-test/1/state",
+		`{"name": "1", "gps": {"lat": 40.1, "lon": 44.5}}`)
+
+	b.dropClients()
+
+	// the source subscribes again once reconnected
+	require.Eventually(t, func() bool {
+		return b.subscribeCount() >= 2
+	}, 5*time.Second, 20*time.Millisecond)
+
+	publish("smogtracker-test/2/state", `{"name": "2", "gps": {"lat": 40.2, "lon": 44.6}}`)
+
+	require.Eventually(t, func() bool {
+		trackers, err := source.Fetch(context.Background())
+		return err == nil && len(trackers) == 2
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestMQTT_New(t *testing.T) {
+	valid := mqttsource.Config{
+		Name:     "diy",
+		Broker:   "tcp://localhost:1883",
+		Topics:   []string{"sensors/+/state"},
+		Template: template,
+	}
+
+	_, err := mqttsource.New(slogdiscard.NewDiscardLogger(), valid)
+	require.NoError(t, err)
+
+	noTopics := valid
+	noTopics.Topics = nil
+
+	badQoS := valid
+	badQoS.QoS = 3
+
+	noId := valid
+	noId.Template.Id = ""
+
+	badLevel := valid
+	badLevel.Template.Id = "topic[x]"
+
+	halfPosition := valid
+	halfPosition.Template.Longitude = ""
+
+	for name, cfg := range map[string]mqttsource.Config{
+		"no topics":     noTopics,
+		"bad qos":       badQoS,
+		"no id":         noId,
+		"bad level":     badLevel,
+		"half position": halfPosition,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := mqttsource.New(slogdiscard.NewDiscardLogger(), cfg)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package mqttsource
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/jsonpath"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+)
+
+type (
+	// Maps a JSON payload to a station and its readings. Fields are JSONPath-like
+	// paths into the payload, topic[n] takes the n-th level of the topic instead
+	Template struct {
+		Id        string
+		Title     string
+		Latitude  string
+		Longitude string
+		// RFC 3339 string or unix seconds, the receive time if empty or missing from the payload
+		ObservedAt string
+		// paths keyed by pollutant
+		Readings map[string]string
+	}
+
+	// Station and readings parsed from a single message
+	Message struct {
+		Tracker models.Tracker
+		// false if the message carries no coordinates, the station keeps its known position
+		HasPosition bool
+		Readings    []models.Measurement
+	}
+
+	field struct {
+		path       jsonpath.Path
+		topicLevel int
+		isTopic    bool
+	}
+
+	compiled struct {
+		source     string
+		id         field
+		title      *field
+		latitude   *field
+		longitude  *field
+		observedAt *field
+		readings   map[models.Pollutant]field
+	}
+)
+
+func (t Template) compile(source string) (*compiled, error) {
+	if len(t.Id) == 0 {
+		return nil, errors.New("template: id is required")
+	}
+	if (len(t.Latitude) == 0) != (len(t.Longitude) == 0) {
+		return nil, errors.New("template: latitude and longitude go together")
+	}
+
+	c := &compiled{
+		source:   source,
+		readings: make(map[models.Pollutant]field, len(t.Readings)),
+	}
+
+	var err error
+	if c.id, err = parseField(t.Id); err != nil {
+		return nil, err
+	}
+
+	optional := []struct {
+		expr string
+		dst  **field
+	}{
+		{t.Title, &c.title},
+		{t.Latitude, &c.latitude},
+		{t.Longitude, &c.longitude},
+		{t.ObservedAt, &c.observedAt},
+	}
+	for _, o := range optional {
+		if len(o.expr) == 0 {
+			continue
+		}
+		f, err := parseField(o.expr)
+		if err != nil {
+			return nil, err
+		}
+		*o.dst = &f
+	}
+
+	for pollutant, expr := range t.Readings {
+		f, err := parseField(expr)
+		if err != nil {
+			return nil, err
+		}
+		c.readings[models.Pollutant(strings.ToLower(pollutant))] = f
+	}
+
+	return c, nil
+}
+
+func parseField(expr string) (field, error) {
+	if rest, ok := strings.CutPrefix(expr, "topic["); ok {
+		level, err := strconv.Atoi(strings.TrimSuffix(rest, "]"))
+		if err != nil || !strings.HasSuffix(rest, "]") || level < 0 {
+			return field{}, fmt.Errorf("template: bad topic level %q", expr)
+		}
+		return field{topicLevel: level, isTopic: true}, nil
+	}
+
+	path, err := jsonpath.Parse(expr)
+	if err != nil {
+		return field{}, fmt.Errorf("template: %w", err)
+	}
+	return field{path: path}, nil
+}
+
+func (f field) text(levels []string, doc any) (string, error) {
+	if !f.isTopic {
+		return f.path.Text(doc)
+	}
+	if f.topicLevel >= len(levels) {
+		return "", fmt.Errorf("topic has no level %d", f.topicLevel)
+	}
+	return levels[f.topicLevel], nil
+}
+
+func (f field) number(levels []string, doc any) (float64, error) {
+	if !f.isTopic {
+		return f.path.Number(doc)
+	}
+	s, err := f.text(levels, doc)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// Parses the message received on the topic, received is used if the template has no observation time.
+// Readings missing from the payload are skipped
+func (c *compiled) parse(topic string, payload []byte, received time.Time) (Message, error) {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return Message{}, fmt.Errorf("payload: %w", err)
+	}
+
+	levels := strings.Split(topic, "/")
+
+	id, err := c.id.text(levels, doc)
+	if err != nil {
+		return Message{}, fmt.Errorf("id: %w", err)
+	}
+	if len(id) == 0 {
+		return Message{}, errors.New("id is empty")
+	}
+
+	msg := Message{Tracker: models.Tracker{OrigId: id, Source: c.source}}
+
+	if c.title != nil {
+		// the title is optional in every message
+		msg.Tracker.Description, _ = c.title.text(levels, doc)
+	}
+
+	if c.latitude != nil {
+		lat, latErr := c.latitude.number(levels, doc)
+		lng, lngErr := c.longitude.number(levels, doc)
+		switch {
+		case latErr == nil && lngErr == nil:
+			if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+				return Message{}, fmt.Errorf("position %f,%f is out of range", lat, lng)
+			}
+			msg.Tracker.Latitude, msg.Tracker.Longitude = lat, lng
+			msg.HasPosition = true
+		case latErr != nil && lngErr != nil:
+		default:
+			return Message{}, errors.New("position is incomplete")
+		}
+	}
+
+	observedAt := received
+	if c.observedAt != nil {
+		// messages without the time are stamped on receive
+		if s, err := c.observedAt.text(levels, doc); err == nil {
+			if observedAt, err = parseTime(s); err != nil {
+				return Message{}, fmt.Errorf("observed at: %w", err)
+			}
+		}
+	}
+
+	for pollutant, f := range c.readings {
+		value, err := f.number(levels, doc)
+		if err != nil {
+			continue
+		}
+		msg.Readings = append(msg.Readings, models.Measurement{
+			TrackerId:  msg.Tracker.Id(),
+			Pollutant:  pollutant,
+			Value:      value,
+			Unit:       models.UnitMicrogramsPerCubicMeter,
+			ObservedAt: observedAt.UTC(),
+		})
+	}
+
+	sort.Slice(msg.Readings, func(i, j int) bool {
+		return msg.Readings[i].Pollutant < msg.Readings[j].Pollutant
+	})
+
+	return msg, nil
+}
+
+// Accepts unix seconds or RFC 3339
+func parseTime(s string) (time.Time, error) {
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
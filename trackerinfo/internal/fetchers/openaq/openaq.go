@@ -0,0 +1,183 @@
+package openaq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+)
+
+const (
+	DefaultURL      = "https://api.openaq.org/v3/locations"
+	DefaultPageSize = 1000
+	sourceName      = "openaq"
+	// protects from paging forever if the API keeps returning full pages
+	maxPages = 1000
+)
+
+type (
+	// Response of the v2 and v3 locations endpoints
+	Response struct {
+		Meta    Meta       `json:"meta"`
+		Results []Location `json:"results"`
+	}
+
+	Meta struct {
+		Page  int `json:"page"`
+		Limit int `json:"limit"`
+		// a number, or a string like ">1000" when v3 doesn't count all the results
+		Found json.RawMessage `json:"found"`
+	}
+
+	Location struct {
+		Id          int          `json:"id"`
+		Name        *string      `json:"name"`
+		Locality    *string      `json:"locality"`
+		Coordinates *Coordinates `json:"coordinates"`
+	}
+
+	Coordinates struct {
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	}
+
+	Config struct {
+		// locations endpoint, DefaultURL if empty
+		URL    string
+		APIKey string
+		// DefaultPageSize if zero
+		PageSize       int
+		UpdateInterval time.Duration
+		// additional query parameters like countries_id or bbox
+		Query map[string]string
+	}
+
+	OpenAQ struct {
+		httpClient *http.Client
+		cfg        Config
+	}
+)
+
+func New(client *http.Client, cfg Config) *OpenAQ {
+	if len(cfg.URL) == 0 {
+		cfg.URL = DefaultURL
+	}
+	if cfg.PageSize <= 0 {
+		cfg.PageSize = DefaultPageSize
+	}
+
+	return &OpenAQ{
+		httpClient: client,
+		cfg:        cfg,
+	}
+}
+
+func (o *OpenAQ) Name() models.SourceName {
+	return sourceName
+}
+
+func (o *OpenAQ) UpdateInterval() time.Duration {
+	return o.cfg.UpdateInterval
+}
+
+// Pages through the locations until a page isn't full.
+// Locations without coordinates are skipped
+func (o *OpenAQ) Fetch(ctx context.Context) ([]models.Tracker, error) {
+	var res []models.Tracker
+
+	for page := 1; page <= maxPages; page++ {
+		decoded, err := o.fetchPage(ctx, page)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", page, err)
+		}
+
+		for _, loc := range decoded.Results {
+			if loc.Coordinates == nil {
+				continue
+			}
+			res = append(res, models.Tracker{
+				OrigId:      strconv.Itoa(loc.Id),
+				Source:      sourceName,
+				Description: loc.description(),
+				Latitude:    loc.Coordinates.Latitude,
+				Longitude:   loc.Coordinates.Longitude,
+			})
+		}
+
+		if len(decoded.Results) < o.cfg.PageSize || decoded.Meta.lastPage(page, o.cfg.PageSize) {
+			return res, nil
+		}
+	}
+
+	return nil, errors.New("too many pages")
+}
+
+func (o *OpenAQ) fetchPage(ctx context.Context, page int) (*Response, error) {
+	u, err := url.Parse(o.cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	for k, v := range o.cfg.Query {
+		q.Set(k, v)
+	}
+	q.Set("limit", strconv.Itoa(o.cfg.PageSize))
+	q.Set("page", strconv.Itoa(page))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if len(o.cfg.APIKey) != 0 {
+		req.Header.Set("X-API-Key", o.cfg.APIKey)
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fetchers.NewStatusError(resp)
+	}
+
+	var decoded Response
+
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	return &decoded, nil
+}
+
+// Name of the location, its locality if the name is missing
+func (l Location) description() string {
+	if l.Name != nil && len(*l.Name) != 0 {
+		return *l.Name
+	}
+	if l.Locality != nil {
+		return *l.Locality
+	}
+	return ""
+}
+
+// Reports whether the page is the last one according to the found count.
+// Found counts given as a string are lower bounds, so they don't end paging
+func (m Meta) lastPage(page, pageSize int) bool {
+	var found int
+	if err := json.Unmarshal(m.Found, &found); err != nil {
+		return false
+	}
+	return page*pageSize >= found
+}
@@ -0,0 +1,128 @@
+package openaq_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/openaq"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type RoundTripFunc func(req *http.Request) *http.Response
+
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
+
+func NewTestClient(f RoundTripFunc) *http.Client {
+	return &http.Client{
+		Transport: RoundTripFunc(f),
+	}
+}
+
+// Serves the recorded responses keyed by the requested url
+func fixtureClient(t *testing.T, fixtures map[string]string) *http.Client {
+	t.Helper()
+
+	return NewTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "secret", req.Header.Get("X-API-Key"))
+
+		name, ok := fixtures[req.URL.String()]
+		if !ok {
+			t.Errorf("unexpected request %s", req.URL)
+			return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: http.NoBody}
+		}
+
+		f, err := os.Open(filepath.Join("testdata", name))
+		require.NoError(t, err)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       f,
+		}
+	})
+}
+
+func TestOpenAQ_Fetch(t *testing.T) {
+	const url = "https://api.openaq.org/v3/locations"
+
+	client := fixtureClient(t, map[string]string{
+		url + "?countries_id=12&limit=2&page=1": "locations_v3_page1.json",
+		url + "?countries_id=12&limit=2&page=2": "locations_v3_page2.json",
+	})
+
+	fetcher := openaq.New(client, openaq.Config{
+		APIKey:         "secret",
+		PageSize:       2,
+		UpdateInterval: time.Hour,
+		Query:          map[string]string{"countries_id": "12"},
+	})
+	assert.Equal(t, models.SourceName("openaq"), fetcher.Name())
+	assert.Equal(t, time.Hour, fetcher.UpdateInterval())
+
+	got, err := fetcher.Fetch(context.Background())
+	require.NoError(t, err)
+
+	want := []models.Tracker{
+		{
+			OrigId:      "8118",
+			Source:      "openaq",
+			Description: "Yerevan-Kentron",
+			Latitude:    40.1811,
+			Longitude:   44.5136,
+		},
+		{
+			OrigId:      "2178",
+			Source:      "openaq",
+			Description: "Gyumri",
+			Latitude:    40.7894,
+			Longitude:   43.8475,
+		},
+	}
+
+	assert.Equal(t, want, got, "the location without coordinates is skipped")
+}
+
+func TestOpenAQ_FetchV2(t *testing.T) {
+	const url = "https://api.openaq.org/v2/locations"
+
+	// the found count ends paging although the page is full
+	client := fixtureClient(t, map[string]string{
+		url + "?limit=2&page=1": "locations_v2.json",
+	})
+
+	fetcher := openaq.New(client, openaq.Config{
+		URL:      url,
+		APIKey:   "secret",
+		PageSize: 2,
+	})
+
+	got, err := fetcher.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "63098", got[1].OrigId)
+	assert.Equal(t, "Nor Nork", got[1].Description)
+}
+
+func TestOpenAQ_FetchError(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Status:     fmt.Sprintf("%d %s", http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized)),
+			Body:       http.NoBody,
+		}
+	})
+
+	fetcher := openaq.New(client, openaq.Config{})
+
+	_, err := fetcher.Fetch(context.Background())
+	assert.ErrorContains(t, err, "401")
+}
@@ -0,0 +1,37 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+)
+
+var ErrPassive = errors.New("push source is not fetched")
+
+// Passive source of a partner posting its stations to the ingestion endpoint.
+// TrackerList doesn't poll it, the batches arrive through TrackerList.Ingest
+type Push struct {
+	name models.SourceName
+}
+
+func New(name string) *Push {
+	return &Push{name: models.SourceName(name)}
+}
+
+func (p *Push) Name() models.SourceName {
+	return p.name
+}
+
+func (p *Push) UpdateInterval() time.Duration {
+	return 0
+}
+
+func (p *Push) Passive() bool {
+	return true
+}
+
+func (p *Push) Fetch(ctx context.Context) ([]models.Tracker, error) {
+	return nil, ErrPassive
+}
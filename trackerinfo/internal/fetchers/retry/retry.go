@@ -0,0 +1,208 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	sl "github.com/MRibalko/smogtracker/pkg/logger"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/services/trackerlist"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	DefaultMaxAttempts = 3
+	DefaultBaseDelay   = time.Second
+	DefaultMaxDelay    = 30 * time.Second
+)
+
+// Zero fields are replaced with the defaults
+type Policy struct {
+	// including the first one
+	MaxAttempts int
+	// delay before the first retry, doubled for every next one
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// Fetcher retrying failed fetches of the wrapped one
+type Retry struct {
+	fetcher  trackerlist.Fetcher
+	policy   Policy
+	log      *slog.Logger
+	attempts metric.Int64Counter
+	giveUps  metric.Int64Counter
+	attrs    metric.MeasurementOption
+}
+
+func New(log *slog.Logger, meter metric.Meter, f trackerlist.Fetcher, policy Policy) (*Retry, error) {
+	const op = "retry.New"
+
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = DefaultMaxAttempts
+	}
+	if policy.BaseDelay == 0 {
+		policy.BaseDelay = DefaultBaseDelay
+	}
+	if policy.MaxDelay == 0 {
+		policy.MaxDelay = max(DefaultMaxDelay, policy.BaseDelay)
+	}
+
+	if policy.MaxAttempts < 0 || policy.BaseDelay < 0 || policy.MaxDelay < policy.BaseDelay {
+		return nil, fmt.Errorf("%s: %s: policy is out of range", op, f.Name())
+	}
+
+	attempts, err := meter.Int64Counter("fetchAttempts",
+		metric.WithDescription("Number of source fetch attempts including retries"),
+		metric.WithUnit("{attempt}"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	giveUps, err := meter.Int64Counter("fetchGiveUps",
+		metric.WithDescription("Number of source fetches failed after all the retries"),
+		metric.WithUnit("{fetch}"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Retry{
+		fetcher:  f,
+		policy:   policy,
+		log:      log.With(slog.String("source", string(f.Name()))),
+		attempts: attempts,
+		giveUps:  giveUps,
+		attrs:    metric.WithAttributes(attribute.String("source", string(f.Name()))),
+	}, nil
+}
+
+func (r *Retry) Name() models.SourceName {
+	return r.fetcher.Name()
+}
+
+func (r *Retry) UpdateInterval() time.Duration {
+	return r.fetcher.UpdateInterval()
+}
+
+// Returns the wrapped fetcher, its optional interfaces are looked up by TrackerList
+func (r *Retry) Unwrap() trackerlist.Fetcher {
+	return r.fetcher
+}
+
+// Fetches until success, a non-retryable error or the last attempt
+func (r *Retry) Fetch(ctx context.Context) ([]models.Tracker, error) {
+	const op = "Retry.Fetch"
+
+	for attempt := 1; ; attempt++ {
+		r.attempts.Add(ctx, 1, r.attrs)
+
+		res, err := r.fetcher.Fetch(ctx)
+		if err == nil {
+			return res, nil
+		}
+
+		if ctx.Err() != nil || !Retryable(err) {
+			return nil, err
+		}
+
+		if attempt >= r.policy.MaxAttempts {
+			r.giveUps.Add(ctx, 1, r.attrs)
+			return nil, fmt.Errorf("%s: gave up after %d attempts: %w", op, attempt, err)
+		}
+
+		delay, ok := r.delay(attempt, err)
+		if !ok {
+			// retrying earlier than asked would be rate limited again,
+			// the source is left to the next update
+			r.giveUps.Add(ctx, 1, r.attrs)
+			return nil, fmt.Errorf("%s: retry after %s exceeds the max delay: %w", op, delay, err)
+		}
+
+		r.log.Warn("fetch failed, retrying",
+			slog.String("op", op),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			sl.Err(err))
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Exponential backoff with jitter, Retry-After of the response takes precedence.
+// Reports false if Retry-After is longer than the max delay
+func (r *Retry) delay(attempt int, err error) (time.Duration, bool) {
+	var statusErr *fetchers.StatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+		return statusErr.RetryAfter, statusErr.RetryAfter <= r.policy.MaxDelay
+	}
+
+	d := r.policy.BaseDelay
+	for i := 1; i < attempt && d < r.policy.MaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, r.policy.MaxDelay)
+
+	// somewhere in [d/2, d] so that sources failing together don't retry together
+	half := d / 2
+	if half <= 0 {
+		return d, true
+	}
+	return half + rand.N(d-half+1), true
+}
+
+// Reports whether the fetch may succeed if repeated: network failures,
+// 5xx and 429 responses
+func Retryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var statusErr *fetchers.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError ||
+			statusErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,195 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MRibalko/smogtracker/pkg/logger/slogdiscard"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/retry"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/services/trackerlist"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+// Fails with the errors one by one, then succeeds
+type flakyFetcher struct {
+	errs  []error
+	calls int
+}
+
+func (f *flakyFetcher) Fetch(ctx context.Context) ([]models.Tracker, error) {
+	f.calls++
+	if f.calls <= len(f.errs) {
+		return nil, f.errs[f.calls-1]
+	}
+	return []models.Tracker{{OrigId: "1", Source: "flaky"}}, nil
+}
+
+func (f *flakyFetcher) Name() models.SourceName {
+	return "flaky"
+}
+
+func (f *flakyFetcher) UpdateInterval() time.Duration {
+	return time.Minute
+}
+
+func newRetry(t *testing.T, f trackerlist.Fetcher, policy retry.Policy) *retry.Retry {
+	t.Helper()
+	r, err := retry.New(slogdiscard.NewDiscardLogger(), otel.Meter("test"), f, policy)
+	require.NoError(t, err)
+	return r
+}
+
+func statusError(code int, retryAfter string) error {
+	resp := &http.Response{StatusCode: code, Header: http.Header{}}
+	if len(retryAfter) != 0 {
+		resp.Header.Set("Retry-After", retryAfter)
+	}
+	return fetchers.NewStatusError(resp)
+}
+
+func TestRetry_Fetch(t *testing.T) {
+	unavailable := statusError(http.StatusServiceUnavailable, "")
+	policy := retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+	cases := []struct {
+		name      string
+		errs      []error
+		calls     int
+		expectErr bool
+	}{
+		{"first attempt", nil, 1, false},
+		{"recovered", []error{unavailable, unavailable}, 3, false},
+		{"gave up", []error{unavailable, unavailable, unavailable}, 3, true},
+		{"not retryable", []error{statusError(http.StatusNotFound, "")}, 1, true},
+		{"network error", []error{&net.OpError{Op: "dial", Err: errors.New("refused")}}, 2, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &flakyFetcher{errs: tc.errs}
+			r := newRetry(t, f, policy)
+
+			res, err := r.Fetch(context.Background())
+			assert.Equal(t, tc.calls, f.calls)
+			if tc.expectErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tc.errs[len(tc.errs)-1])
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, res, 1)
+		})
+	}
+}
+
+func TestRetry_FetchCanceled(t *testing.T) {
+	f := &flakyFetcher{errs: []error{statusError(http.StatusBadGateway, ""), nil}}
+	r := newRetry(t, f, retry.Policy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := r.Fetch(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, f.calls)
+}
+
+func TestRetry_RetryAfter(t *testing.T) {
+	t.Run("Within the max delay", func(t *testing.T) {
+		f := &flakyFetcher{errs: []error{statusError(http.StatusTooManyRequests, "1")}}
+		r := newRetry(t, f, retry.Policy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Second})
+
+		start := time.Now()
+		_, err := r.Fetch(context.Background())
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), time.Second, "no retry before the requested time")
+		assert.Equal(t, 2, f.calls)
+	})
+
+	t.Run("Beyond the max delay", func(t *testing.T) {
+		f := &flakyFetcher{errs: []error{statusError(http.StatusTooManyRequests, "120")}}
+		r := newRetry(t, f, retry.Policy{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: time.Second})
+
+		start := time.Now()
+		_, err := r.Fetch(context.Background())
+		var statusErr *fetchers.StatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+		assert.Equal(t, 1, f.calls, "the source isn't retried early")
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
+func TestRetry_HTTPServer(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	f := &httpFetcher{url: srv.URL}
+	r := newRetry(t, f, retry.Policy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	_, err := r.Fetch(context.Background())
+	var statusErr *fetchers.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusInternalServerError, statusErr.StatusCode)
+	assert.Equal(t, 2, requests)
+}
+
+type httpFetcher struct {
+	flakyFetcher
+	url string
+}
+
+func (f *httpFetcher) Fetch(ctx context.Context) ([]models.Tracker, error) {
+	resp, err := http.Get(f.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return nil, fetchers.NewStatusError(resp)
+}
+
+func TestRetryable(t *testing.T) {
+	cases := []struct {
+		err       error
+		retryable bool
+	}{
+		{statusError(http.StatusInternalServerError, ""), true},
+		{statusError(http.StatusTooManyRequests, "10"), true},
+		{statusError(http.StatusUnauthorized, ""), false},
+		{fmt.Errorf("decode: %w", io.ErrUnexpectedEOF), true},
+		{&net.DNSError{Err: "no such host", IsNotFound: true}, false},
+		{&net.DNSError{Err: "timeout", IsTimeout: true}, true},
+		{context.Canceled, false},
+		{errors.New("invalid character"), false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tc.retryable, retry.Retryable(tc.err))
+		})
+	}
+}
+
+func TestNew_Policy(t *testing.T) {
+	_, err := retry.New(slogdiscard.NewDiscardLogger(), otel.Meter("test"), &flakyFetcher{},
+		retry.Policy{BaseDelay: time.Minute, MaxDelay: time.Second})
+	require.Error(t, err)
+
+	_, err = retry.New(slogdiscard.NewDiscardLogger(), otel.Meter("test"), &flakyFetcher{}, retry.Policy{})
+	require.NoError(t, err)
+}
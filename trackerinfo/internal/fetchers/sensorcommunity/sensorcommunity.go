@@ -0,0 +1,158 @@
+package sensorcommunity
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+)
+
+const (
+	// averages of the last 5 minutes of all the sensors,
+	// https://data.sensor.community/airrohr/v1/filter/country=AM returns the same format
+	DefaultURL = "https://data.sensor.community/static/v2/data.json"
+	sourceName = "sensorcommunity"
+)
+
+type (
+	// Reading of a single sensor, a location usually hosts several sensors
+	Entry struct {
+		Id               int64             `json:"id"`
+		Timestamp        string            `json:"timestamp"`
+		Location         Location          `json:"location"`
+		Sensor           Sensor            `json:"sensor"`
+		SensorDataValues []SensorDataValue `json:"sensordatavalues"`
+	}
+
+	Location struct {
+		Id int64 `json:"id"`
+		// coordinates are strings in the feed
+		Latitude  string `json:"latitude"`
+		Longitude string `json:"longitude"`
+		Country   string `json:"country"`
+		Indoor    int    `json:"indoor"`
+	}
+
+	Sensor struct {
+		Id         int64      `json:"id"`
+		SensorType SensorType `json:"sensor_type"`
+	}
+
+	SensorType struct {
+		Id           int64  `json:"id"`
+		Name         string `json:"name"`
+		Manufacturer string `json:"manufacturer"`
+	}
+
+	SensorDataValue struct {
+		Value     string `json:"value"`
+		ValueType string `json:"value_type"`
+	}
+
+	SensorCommunity struct {
+		httpClient     *http.Client
+		url            string
+		updateInterval time.Duration
+	}
+)
+
+// Uses DefaultURL if url is empty
+func New(client *http.Client, url string, updateInterval time.Duration) *SensorCommunity {
+	if len(url) == 0 {
+		url = DefaultURL
+	}
+
+	return &SensorCommunity{
+		httpClient:     client,
+		url:            url,
+		updateInterval: updateInterval,
+	}
+}
+
+func (s *SensorCommunity) Name() models.SourceName {
+	return sourceName
+}
+
+func (s *SensorCommunity) UpdateInterval() time.Duration {
+	return s.updateInterval
+}
+
+// Groups the sensor entries by location, a tracker is described by the sensor types of its location.
+// Locations without valid coordinates are skipped
+func (s *SensorCommunity) Fetch(ctx context.Context) ([]models.Tracker, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fetchers.NewStatusError(resp)
+	}
+
+	var decoded []Entry
+
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	// locations in the order of their first entry
+	var order []int64
+	types := make(map[int64]map[string]struct{})
+	locations := make(map[int64]Location)
+
+	for _, e := range decoded {
+		id := e.Location.Id
+		if _, exists := locations[id]; !exists {
+			order = append(order, id)
+			locations[id] = e.Location
+			types[id] = make(map[string]struct{})
+		}
+		if name := e.Sensor.SensorType.Name; len(name) != 0 {
+			types[id][name] = struct{}{}
+		}
+	}
+
+	res := make([]models.Tracker, 0, len(order))
+
+	for _, id := range order {
+		loc := locations[id]
+
+		lat, err := strconv.ParseFloat(loc.Latitude, 64)
+		if err != nil || lat < -90 || lat > 90 {
+			continue
+		}
+		lng, err := strconv.ParseFloat(loc.Longitude, 64)
+		if err != nil || lng < -180 || lng > 180 {
+			continue
+		}
+
+		names := make([]string, 0, len(types[id]))
+		for name := range types[id] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		res = append(res, models.Tracker{
+			OrigId:      strconv.FormatInt(id, 10),
+			Source:      sourceName,
+			Description: strings.Join(names, ", "),
+			Latitude:    lat,
+			Longitude:   lng,
+		})
+	}
+
+	return res, nil
+}
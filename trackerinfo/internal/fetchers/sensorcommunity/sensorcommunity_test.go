@@ -0,0 +1,68 @@
+package sensorcommunity_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/sensorcommunity"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type RoundTripFunc func(req *http.Request) *http.Response
+
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
+
+func NewTestClient(f RoundTripFunc) *http.Client {
+	return &http.Client{
+		Transport: RoundTripFunc(f),
+	}
+}
+
+func TestSensorCommunity_Fetch(t *testing.T) {
+	testClient := NewTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, sensorcommunity.DefaultURL, req.URL.String())
+
+		f, err := os.Open(filepath.Join("testdata", "data.json"))
+		require.NoError(t, err)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       f,
+		}
+	})
+
+	fetcher := sensorcommunity.New(testClient, "", 5*time.Minute)
+	assert.Equal(t, models.SourceName("sensorcommunity"), fetcher.Name())
+	assert.Equal(t, 5*time.Minute, fetcher.UpdateInterval())
+
+	got, err := fetcher.Fetch(context.Background())
+	require.NoError(t, err)
+
+	want := []models.Tracker{
+		{
+			OrigId:      "61242",
+			Source:      "sensorcommunity",
+			Description: "BME280, SDS011",
+			Latitude:    40.182,
+			Longitude:   44.516,
+		},
+		{
+			OrigId:      "70133",
+			Source:      "sensorcommunity",
+			Description: "SDS011",
+			Latitude:    40.2,
+			Longitude:   44.582,
+		},
+	}
+
+	assert.Equal(t, want, got, "entries are grouped by location, the location without coordinates is skipped")
+}
@@ -0,0 +1,97 @@
+package geo
+
+import "math"
+
+// Mean Earth radius in meters
+const EarthRadius = 6371008.8
+
+type (
+	Point struct {
+		Latitude  float64
+		Longitude float64
+	}
+
+	// Latitude/longitude rectangle. West is greater than East
+	// when the rectangle crosses the antimeridian
+	Bounds struct {
+		South float64
+		West  float64
+		North float64
+		East  float64
+	}
+)
+
+// Returns the great-circle distance between the points in meters
+func Distance(a, b Point) float64 {
+	lat1, lat2 := radians(a.Latitude), radians(b.Latitude)
+	dLat := lat2 - lat1
+	dLng := radians(b.Longitude - a.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Returns the smallest rectangle containing the circle around the point.
+// The rectangle covers all longitudes if the circle reaches a pole
+func BoundsAround(p Point, radius float64) Bounds {
+	angular := radius / EarthRadius
+	dLat := degrees(angular)
+
+	b := Bounds{
+		South: p.Latitude - dLat,
+		North: p.Latitude + dLat,
+	}
+
+	if b.South <= -90 || b.North >= 90 {
+		b.South = math.Max(b.South, -90)
+		b.North = math.Min(b.North, 90)
+		b.West, b.East = -180, 180
+		return b
+	}
+
+	dLng := degrees(math.Asin(math.Sin(angular) / math.Cos(radians(p.Latitude))))
+	if dLng >= 180 {
+		b.West, b.East = -180, 180
+		return b
+	}
+
+	b.West = NormalizeLongitude(p.Longitude - dLng)
+	b.East = NormalizeLongitude(p.Longitude + dLng)
+	return b
+}
+
+func (b Bounds) CrossesAntimeridian() bool {
+	return b.West > b.East
+}
+
+func (b Bounds) Contains(p Point) bool {
+	if p.Latitude < b.South || p.Latitude > b.North {
+		return false
+	}
+	if b.CrossesAntimeridian() {
+		return p.Longitude >= b.West || p.Longitude <= b.East
+	}
+	return p.Longitude >= b.West && p.Longitude <= b.East
+}
+
+// Brings the longitude to the range [-180, 180]
+func NormalizeLongitude(lng float64) float64 {
+	if lng >= -180 && lng <= 180 {
+		return lng
+	}
+	lng = math.Mod(lng+180, 360)
+	if lng < 0 {
+		lng += 360
+	}
+	return lng - 180
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -0,0 +1,65 @@
+package geo_test
+
+import (
+	"testing"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/geo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistance(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b geo.Point
+		want float64
+	}{
+		{"same point", geo.Point{40.182, 44.516}, geo.Point{40.182, 44.516}, 0},
+		{"Kentron to Nor Nork", geo.Point{40.182, 44.516}, geo.Point{40.2, 44.582}, 5953},
+		{"across antimeridian", geo.Point{0, 179.5}, geo.Point{0, -179.5}, 111195},
+		{"Yerevan to Tbilisi", geo.Point{40.1792, 44.4991}, geo.Point{41.7151, 44.8271}, 172992},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.InDelta(t, tt.want, geo.Distance(tt.a, tt.b), 1)
+		})
+	}
+}
+
+func TestBoundsAround(t *testing.T) {
+	t.Run("contains the circle", func(t *testing.T) {
+		center := geo.Point{40.182, 44.516}
+		b := geo.BoundsAround(center, 10000)
+		require.False(t, b.CrossesAntimeridian())
+
+		for _, p := range []geo.Point{
+			{b.North, center.Longitude},
+			{b.South, center.Longitude},
+			{center.Latitude, b.East},
+			{center.Latitude, b.West},
+		} {
+			require.True(t, b.Contains(p))
+			require.GreaterOrEqual(t, geo.Distance(center, p), 9999.0)
+		}
+	})
+
+	t.Run("crosses antimeridian", func(t *testing.T) {
+		b := geo.BoundsAround(geo.Point{0, 179.9}, 50000)
+		require.True(t, b.CrossesAntimeridian())
+		require.True(t, b.Contains(geo.Point{0, -179.9}))
+		require.True(t, b.Contains(geo.Point{0, 179.8}))
+		require.False(t, b.Contains(geo.Point{0, 0}))
+	})
+
+	t.Run("reaches pole", func(t *testing.T) {
+		b := geo.BoundsAround(geo.Point{89.9, 0}, 50000)
+		require.Equal(t, geo.Bounds{South: b.South, West: -180, North: 90, East: 180}, b)
+	})
+}
+
+func TestNormalizeLongitude(t *testing.T) {
+	require.Equal(t, 170.0, geo.NormalizeLongitude(-190))
+	require.Equal(t, -170.0, geo.NormalizeLongitude(190))
+	require.Equal(t, 180.0, geo.NormalizeLongitude(180))
+	require.Equal(t, 10.0, geo.NormalizeLongitude(370))
+}
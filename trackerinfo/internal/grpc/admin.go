@@ -0,0 +1,133 @@
+package trackerinfogrpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+
+	"github.com/MRibalko/smogtracker/protos/gen/trackerinfov1"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/services/trackerlist"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Admin interface {
+	PendingRefreshes(ctx context.Context) ([]models.PendingRefresh, error)
+	ConfirmRefresh(ctx context.Context, source models.SourceName) error
+	DiscardRefresh(ctx context.Context, source models.SourceName) error
+}
+
+type adminAPI struct {
+	trackerinfov1.UnimplementedTrackerInfoAdminServer
+	adminService Admin
+}
+
+// The server must be created with AdminAuthInterceptor and AdminAuthStreamInterceptor
+func RegisterAdmin(gRPCServer *grpc.Server, adminService Admin) {
+	trackerinfov1.RegisterTrackerInfoAdminServer(gRPCServer, &adminAPI{adminService: adminService})
+}
+
+var adminPrefix = "/" + trackerinfov1.TrackerInfoAdmin_ServiceDesc.ServiceName + "/"
+
+// Rejects calls of the admin service without the token in the "authorization: Bearer" metadata.
+// Calls of other services are passed through
+func AdminAuthInterceptor(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if strings.HasPrefix(info.FullMethod, adminPrefix) && !adminAuthorized(ctx, token) {
+			return nil, status.Error(codes.Unauthenticated, "invalid admin token")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Streaming counterpart of AdminAuthInterceptor
+func AdminAuthStreamInterceptor(token string) grpc.StreamServerInterceptor {
+	return func(srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if strings.HasPrefix(info.FullMethod, adminPrefix) && !adminAuthorized(ss.Context(), token) {
+			return status.Error(codes.Unauthenticated, "invalid admin token")
+		}
+		return handler(srv, ss)
+	}
+}
+
+func adminAuthorized(ctx context.Context, token string) bool {
+	if len(token) == 0 {
+		return false
+	}
+	for _, v := range metadata.ValueFromIncomingContext(ctx, "authorization") {
+		got, found := strings.CutPrefix(v, "Bearer ")
+		if found && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *adminAPI) PendingRefreshes(
+	ctx context.Context,
+	in *trackerinfov1.EmptyRequest,
+) (*trackerinfov1.PendingRefreshesResponse, error) {
+	list, err := s.adminService.PendingRefreshes(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	var result []*trackerinfov1.PendingRefresh
+	for _, v := range list {
+		result = append(result, &trackerinfov1.PendingRefresh{
+			Source:    string(v.Source),
+			Current:   int32(v.Current),
+			Fetched:   int32(len(v.Trackers)),
+			Deletes:   int32(v.Deletes),
+			RefusedAt: timestamppb.New(v.RefusedAt),
+		})
+	}
+	return &trackerinfov1.PendingRefreshesResponse{Result: result}, nil
+}
+
+func (s *adminAPI) ConfirmRefresh(
+	ctx context.Context,
+	in *trackerinfov1.SourceRequest,
+) (*trackerinfov1.EmptyResponse, error) {
+	if len(in.Source) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "source is empty")
+	}
+
+	if err := s.adminService.ConfirmRefresh(ctx, models.SourceName(in.Source)); err != nil {
+		if errors.Is(err, trackerlist.ErrNoPendingRefresh) {
+			return nil, status.Error(codes.NotFound, "no pending refresh")
+		}
+		return nil, status.Error(codes.Internal, "storage error")
+	}
+	return &trackerinfov1.EmptyResponse{}, nil
+}
+
+func (s *adminAPI) DiscardRefresh(
+	ctx context.Context,
+	in *trackerinfov1.SourceRequest,
+) (*trackerinfov1.EmptyResponse, error) {
+	if len(in.Source) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "source is empty")
+	}
+
+	if err := s.adminService.DiscardRefresh(ctx, models.SourceName(in.Source)); err != nil {
+		if errors.Is(err, trackerlist.ErrNoPendingRefresh) {
+			return nil, status.Error(codes.NotFound, "no pending refresh")
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	return &trackerinfov1.EmptyResponse{}, nil
+}
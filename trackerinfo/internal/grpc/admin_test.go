@@ -0,0 +1,79 @@
+package trackerinfogrpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MRibalko/smogtracker/protos/gen/trackerinfov1"
+	trackerinfogrpc "github.com/MRibalko/smogtracker/trackerinfo/internal/grpc"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminAuthInterceptor(t *testing.T) {
+	cases := []struct {
+		name   string
+		token  string
+		method string
+		auth   string
+		code   codes.Code
+	}{
+		{"valid token", "secret", trackerinfov1.TrackerInfoAdmin_ConfirmRefresh_FullMethodName, "Bearer secret", codes.OK},
+		{"wrong token", "secret", trackerinfov1.TrackerInfoAdmin_ConfirmRefresh_FullMethodName, "Bearer other", codes.Unauthenticated},
+		{"no token", "secret", trackerinfov1.TrackerInfoAdmin_PendingRefreshes_FullMethodName, "", codes.Unauthenticated},
+		{"not bearer", "secret", trackerinfov1.TrackerInfoAdmin_DiscardRefresh_FullMethodName, "secret", codes.Unauthenticated},
+		{"token not configured", "", trackerinfov1.TrackerInfoAdmin_DiscardRefresh_FullMethodName, "Bearer ", codes.Unauthenticated},
+		{"read api", "secret", trackerinfov1.TrackerInfo_Sources_FullMethodName, "", codes.OK},
+		{"source status", "secret", trackerinfov1.TrackerInfo_SourceStatus_FullMethodName, "", codes.OK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if len(tc.auth) != 0 {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tc.auth))
+			}
+
+			called := false
+			_, err := trackerinfogrpc.AdminAuthInterceptor(tc.token)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.method},
+				func(ctx context.Context, req any) (any, error) {
+					called = true
+					return nil, nil
+				})
+
+			assert.Equal(t, tc.code, status.Code(err))
+			assert.Equal(t, tc.code == codes.OK, called)
+		})
+	}
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestAdminAuthStreamInterceptor(t *testing.T) {
+	interceptor := trackerinfogrpc.AdminAuthStreamInterceptor("secret")
+	handler := func(srv any, stream grpc.ServerStream) error { return nil }
+
+	// no admin streams yet, the method name is what is checked
+	err := interceptor(nil, &testServerStream{ctx: context.Background()},
+		&grpc.StreamServerInfo{FullMethod: "/trackerinfo.TrackerInfoAdmin/Watch"}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer secret"))
+	err = interceptor(nil, &testServerStream{ctx: ctx},
+		&grpc.StreamServerInfo{FullMethod: "/trackerinfo.TrackerInfoAdmin/Watch"}, handler)
+	assert.NoError(t, err)
+
+	err = interceptor(nil, &testServerStream{ctx: context.Background()},
+		&grpc.StreamServerInfo{FullMethod: trackerinfov1.TrackerInfo_Watch_FullMethodName}, handler)
+	assert.NoError(t, err)
+}
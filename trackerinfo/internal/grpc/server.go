@@ -2,13 +2,17 @@ package trackerinfogrpc
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/MRibalko/smogtracker/protos/gen/trackerinfov1"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/geo"
 	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type TrackerInfo interface {
@@ -16,6 +20,17 @@ type TrackerInfo interface {
 	IdsBySource(ctx context.Context, source string) ([]string, error)
 	List(ctx context.Context) ([]models.Tracker, error)
 	ListSince(ctx context.Context, modifiedFrom time.Time) ([]models.Tracker, error)
+	ListAfter(ctx context.Context, revision models.Revision) ([]models.Tracker, error)
+	Readings(ctx context.Context,
+		source, origId string,
+		pollutant models.Pollutant,
+		from, to time.Time,
+	) ([]models.Measurement, error)
+	Watch(ctx context.Context, source models.SourceName, after models.Revision) (<-chan models.Event, error)
+	Nearest(ctx context.Context, point geo.Point, radius float64, limit int) ([]models.NearTracker, error)
+	ListInBounds(ctx context.Context, bounds geo.Bounds, sources []string) ([]models.Tracker, error)
+	History(ctx context.Context, source, origId string) ([]models.TrackerVersion, error)
+	SourceStatus(ctx context.Context, source models.SourceName) ([]models.SourceHealth, error)
 }
 
 type serverAPI struct {
@@ -69,10 +84,13 @@ func (s *serverAPI) List(
 		err  error
 	)
 
-	if err = in.From.CheckValid(); err != nil {
-		list, err = s.infoService.List(ctx)
-	} else {
+	switch {
+	case in.From.CheckValid() == nil:
 		list, err = s.infoService.ListSince(ctx, in.From.AsTime())
+	case in.AfterRevision > 0:
+		list, err = s.infoService.ListAfter(ctx, models.Revision(in.AfterRevision))
+	default:
+		list, err = s.infoService.List(ctx)
 	}
 
 	if err != nil {
@@ -83,16 +101,266 @@ func (s *serverAPI) List(
 		return nil, status.Error(codes.NotFound, "no data")
 	}
 
+	// the high-water mark to request the following changes with
+	revision := in.AfterRevision
+
 	var result []*trackerinfov1.TrackerFullInfo
 	for _, v := range list {
-		info := trackerinfov1.TrackerFullInfo{
-			OrigId:      v.OrigId,
-			Source:      v.Source,
-			Description: v.Description,
-			Latitude:    v.Latitude,
-			Longitude:   v.Longitude,
+		revision = max(revision, int64(v.Revision))
+		result = append(result, trackerFullInfo(v))
+	}
+	return &trackerinfov1.FullInfoResponse{Result: result, Revision: revision}, nil
+}
+
+func (s *serverAPI) Readings(
+	ctx context.Context,
+	in *trackerinfov1.ReadingsRequest,
+) (*trackerinfov1.ReadingsResponse, error) {
+	if len(in.Source) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "source is empty")
+	}
+	if len(in.OrigId) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "orig_id is empty")
+	}
+	if err := in.From.CheckValid(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "from is not valid")
+	}
+	if err := in.To.CheckValid(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "to is not valid")
+	}
+
+	from, to := in.From.AsTime(), in.To.AsTime()
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+
+	list, err := s.infoService.Readings(ctx, in.Source, in.OrigId, models.Pollutant(in.Pollutant), from, to)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "storage error")
+	}
+
+	if len(list) == 0 {
+		return nil, status.Error(codes.NotFound, "no data")
+	}
+
+	var result []*trackerinfov1.Reading
+	for _, v := range list {
+		reading := trackerinfov1.Reading{
+			Pollutant:  string(v.Pollutant),
+			Value:      v.Value,
+			Unit:       v.Unit,
+			ObservedAt: timestamppb.New(v.ObservedAt),
+		}
+		result = append(result, &reading)
+	}
+	return &trackerinfov1.ReadingsResponse{Result: result}, nil
+}
+
+func (s *serverAPI) Watch(
+	in *trackerinfov1.WatchRequest,
+	stream trackerinfov1.TrackerInfo_WatchServer,
+) error {
+	if in.AfterRevision < 0 {
+		return status.Error(codes.InvalidArgument, "after_revision is negative")
+	}
+
+	ctx := stream.Context()
+
+	events, err := s.infoService.Watch(ctx, models.SourceName(in.Source), models.Revision(in.AfterRevision))
+	if err != nil {
+		if errors.Is(err, storage.ErrSourceNotFound) {
+			return status.Error(codes.NotFound, "no source")
+		}
+		return status.Error(codes.Internal, "storage error")
+	}
+
+	for ev := range events {
+		err := stream.Send(&trackerinfov1.WatchEvent{
+			Type:    changeTypes[ev.Type],
+			Tracker: trackerFullInfo(ev.Tracker),
+		})
+		if err != nil {
+			return err
 		}
-		result = append(result, &info)
+	}
+
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return status.Error(codes.Unavailable, "watch interrupted, resume from the last received revision")
+}
+
+// Upper limit of trackers returned by geospatial queries
+const maxLimit = 1000
+
+func (s *serverAPI) Nearest(
+	ctx context.Context,
+	in *trackerinfov1.NearestRequest,
+) (*trackerinfov1.NearestResponse, error) {
+	if in.Latitude < -90 || in.Latitude > 90 {
+		return nil, status.Error(codes.InvalidArgument, "latitude is out of range")
+	}
+	if in.Longitude < -180 || in.Longitude > 180 {
+		return nil, status.Error(codes.InvalidArgument, "longitude is out of range")
+	}
+	if in.Radius <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "radius must be positive")
+	}
+	if in.Limit <= 0 || in.Limit > maxLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be in range 1..%d", maxLimit)
+	}
+
+	point := geo.Point{Latitude: in.Latitude, Longitude: in.Longitude}
+
+	list, err := s.infoService.Nearest(ctx, point, in.Radius, int(in.Limit))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "storage error")
+	}
+
+	if len(list) == 0 {
+		return nil, status.Error(codes.NotFound, "no data")
+	}
+
+	var result []*trackerinfov1.NearTracker
+	for _, v := range list {
+		result = append(result, &trackerinfov1.NearTracker{
+			Tracker:  trackerFullInfo(v.Tracker),
+			Distance: v.Distance,
+		})
+	}
+	return &trackerinfov1.NearestResponse{Result: result}, nil
+}
+
+func (s *serverAPI) ListInBounds(
+	ctx context.Context,
+	in *trackerinfov1.BoundsRequest,
+) (*trackerinfov1.FullInfoResponse, error) {
+	if in.South < -90 || in.North > 90 || in.South > in.North {
+		return nil, status.Error(codes.InvalidArgument, "latitudes are out of range")
+	}
+	if in.West < -180 || in.West > 180 || in.East < -180 || in.East > 180 {
+		return nil, status.Error(codes.InvalidArgument, "longitudes are out of range")
+	}
+
+	bounds := geo.Bounds{South: in.South, West: in.West, North: in.North, East: in.East}
+
+	list, err := s.infoService.ListInBounds(ctx, bounds, in.Sources)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "storage error")
+	}
+
+	if len(list) == 0 {
+		return nil, status.Error(codes.NotFound, "no data")
+	}
+
+	var result []*trackerinfov1.TrackerFullInfo
+	for _, v := range list {
+		result = append(result, trackerFullInfo(v))
 	}
 	return &trackerinfov1.FullInfoResponse{Result: result}, nil
 }
+
+func (s *serverAPI) TrackerHistory(
+	ctx context.Context,
+	in *trackerinfov1.TrackerRequest,
+) (*trackerinfov1.TrackerHistoryResponse, error) {
+	if len(in.Source) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "source is empty")
+	}
+	if len(in.OrigId) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "orig_id is empty")
+	}
+
+	list, err := s.infoService.History(ctx, in.Source, in.OrigId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "storage error")
+	}
+
+	if len(list) == 0 {
+		return nil, status.Error(codes.NotFound, "no tracker")
+	}
+
+	var result []*trackerinfov1.TrackerVersion
+	for _, v := range list {
+		version := trackerinfov1.TrackerVersion{
+			Tracker:   trackerFullInfo(v.Tracker),
+			Kind:      versionKinds[v.Kind],
+			ValidFrom: timestamppb.New(v.ValidFrom),
+		}
+		if !v.ValidTo.IsZero() {
+			version.ValidTo = timestamppb.New(v.ValidTo)
+		}
+		result = append(result, &version)
+	}
+	return &trackerinfov1.TrackerHistoryResponse{Result: result}, nil
+}
+
+func (s *serverAPI) SourceStatus(
+	ctx context.Context,
+	in *trackerinfov1.SourceRequest,
+) (*trackerinfov1.SourceStatusResponse, error) {
+	list, err := s.infoService.SourceStatus(ctx, models.SourceName(in.Source))
+	if err != nil {
+		if errors.Is(err, storage.ErrSourceNotFound) {
+			return nil, status.Error(codes.NotFound, "no source")
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	var result []*trackerinfov1.SourceStatus
+	for _, v := range list {
+		sourceStatus := trackerinfov1.SourceStatus{
+			Source:              string(v.Source),
+			State:               sourceStates[v.State],
+			LastError:           v.LastError,
+			ConsecutiveFailures: int32(v.ConsecutiveFailures),
+		}
+		if !v.LastSuccess.IsZero() {
+			sourceStatus.LastSuccess = timestamppb.New(v.LastSuccess)
+		}
+		if !v.LastErrorAt.IsZero() {
+			sourceStatus.LastErrorAt = timestamppb.New(v.LastErrorAt)
+		}
+		if !v.RetryAt.IsZero() {
+			sourceStatus.RetryAt = timestamppb.New(v.RetryAt)
+		}
+		result = append(result, &sourceStatus)
+	}
+	return &trackerinfov1.SourceStatusResponse{Result: result}, nil
+}
+
+var changeTypes = map[models.ChangeType]trackerinfov1.ChangeType{
+	models.ChangeInserted:  trackerinfov1.ChangeType_CHANGE_TYPE_INSERTED,
+	models.ChangeUpdated:   trackerinfov1.ChangeType_CHANGE_TYPE_UPDATED,
+	models.ChangeDeleted:   trackerinfov1.ChangeType_CHANGE_TYPE_DELETED,
+	models.ChangeRelocated: trackerinfov1.ChangeType_CHANGE_TYPE_RELOCATED,
+}
+
+var versionKinds = map[models.VersionKind]trackerinfov1.VersionKind{
+	models.VersionCreated: trackerinfov1.VersionKind_VERSION_KIND_CREATED,
+	models.VersionMoved:   trackerinfov1.VersionKind_VERSION_KIND_MOVED,
+	models.VersionRenamed: trackerinfov1.VersionKind_VERSION_KIND_RENAMED,
+	models.VersionRemoved: trackerinfov1.VersionKind_VERSION_KIND_REMOVED,
+}
+
+var sourceStates = map[models.SourceState]trackerinfov1.SourceState{
+	models.SourceHealthy:     trackerinfov1.SourceState_SOURCE_STATE_HEALTHY,
+	models.SourceDegraded:    trackerinfov1.SourceState_SOURCE_STATE_DEGRADED,
+	models.SourceOpenCircuit: trackerinfov1.SourceState_SOURCE_STATE_OPEN_CIRCUIT,
+}
+
+func trackerFullInfo(v models.Tracker) *trackerinfov1.TrackerFullInfo {
+	info := trackerinfov1.TrackerFullInfo{
+		OrigId:      v.OrigId,
+		Source:      v.Source,
+		Description: v.Description,
+		Latitude:    v.Latitude,
+		Longitude:   v.Longitude,
+		Revision:    int64(v.Revision),
+	}
+	if v.IsDeleted() {
+		info.Deleted = true
+		info.DeletedAt = timestamppb.New(v.DeletedAt)
+	}
+	return &info
+}
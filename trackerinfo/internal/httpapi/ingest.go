@@ -0,0 +1,246 @@
+package httpapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	sl "github.com/MRibalko/smogtracker/pkg/logger"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/services/trackerlist"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage"
+)
+
+// Size limit of a pushed batch
+const maxBatchBytes = 10 << 20
+
+type (
+	Ingester interface {
+		Ingest(ctx context.Context,
+			source models.SourceName,
+			trackers []models.Tracker,
+			measurements []models.Measurement,
+		) error
+	}
+
+	// Full station list of the source along with optional readings
+	Batch struct {
+		Stations []Station `json:"stations"`
+		Readings []Reading `json:"readings"`
+	}
+
+	Station struct {
+		Id        string   `json:"id"`
+		Title     string   `json:"title"`
+		Latitude  *float64 `json:"latitude"`
+		Longitude *float64 `json:"longitude"`
+	}
+
+	Reading struct {
+		StationId string  `json:"station_id"`
+		Pollutant string  `json:"pollutant"`
+		Value     float64 `json:"value"`
+		// µg/m³ if empty
+		Unit       string    `json:"unit"`
+		ObservedAt time.Time `json:"observed_at"`
+	}
+
+	IngestResponse struct {
+		Stations int         `json:"stations"`
+		Readings int         `json:"readings"`
+		Errors   []ItemError `json:"errors,omitempty"`
+	}
+
+	// Validation error of a batch item, Item is like stations[2] or readings[0]
+	ItemError struct {
+		Item  string `json:"item"`
+		Error string `json:"error"`
+	}
+
+	ingestHandler struct {
+		log      *slog.Logger
+		ingester Ingester
+		// bearer tokens keyed by source name
+		tokens map[string]string
+		now    func() time.Time
+	}
+)
+
+// Registers POST /v1/sources/{source}/batch. Each push source is authenticated by its own bearer token
+func RegisterIngest(mux *http.ServeMux, log *slog.Logger, ingester Ingester, tokens map[string]string) {
+	h := &ingestHandler{
+		log:      log,
+		ingester: ingester,
+		tokens:   tokens,
+		now:      time.Now,
+	}
+
+	mux.HandleFunc("POST /v1/sources/{source}/batch", h.ingest)
+}
+
+// Validates the whole batch first, a batch with an invalid item isn't applied
+func (h *ingestHandler) ingest(w http.ResponseWriter, r *http.Request) {
+	const op = "httpapi.ingest"
+	source := r.PathValue("source")
+	log := h.log.With(slog.String("op", op), slog.String("source", source))
+
+	token, exists := h.tokens[source]
+	if !exists {
+		writeError(w, http.StatusNotFound, "source not found")
+		return
+	}
+	if !authorized(r, token) {
+		writeError(w, http.StatusUnauthorized, "bad token")
+		return
+	}
+
+	var batch Batch
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&batch); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("malformed batch: %s", err))
+		return
+	}
+
+	trackers, measurements, errs := h.validate(source, batch)
+	if len(errs) != 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, IngestResponse{Errors: errs})
+		return
+	}
+
+	err := h.ingester.Ingest(r.Context(), models.SourceName(source), trackers, measurements)
+	if errors.Is(err, storage.ErrSourceNotFound) {
+		writeError(w, http.StatusNotFound, "source not found")
+		return
+	}
+	if errors.Is(err, trackerlist.ErrNotPassive) {
+		writeError(w, http.StatusConflict, "source is polled")
+		return
+	}
+	if errors.Is(err, trackerlist.ErrRefreshPending) {
+		log.Warn("batch refused by the deletion guard", slog.Int("stations", len(trackers)))
+		writeError(w, http.StatusConflict, "batch deletes too many stations, nothing applied until an operator confirms it")
+		return
+	}
+	if err != nil {
+		log.Error("batch ingestion failed", sl.Err(err))
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	log.Info("batch ingested", slog.Int("stations", len(trackers)), slog.Int("readings", len(measurements)))
+	writeJSON(w, http.StatusOK, IngestResponse{
+		Stations: len(trackers),
+		Readings: len(measurements),
+	})
+}
+
+func (h *ingestHandler) validate(source string, batch Batch) ([]models.Tracker, []models.Measurement, []ItemError) {
+	var errs []ItemError
+	fail := func(item string, i int, msg string) {
+		errs = append(errs, ItemError{Item: fmt.Sprintf("%s[%d]", item, i), Error: msg})
+	}
+
+	if len(batch.Stations) == 0 {
+		errs = append(errs, ItemError{Item: "stations", Error: "empty"})
+	}
+
+	trackers := make([]models.Tracker, 0, len(batch.Stations))
+	seen := make(map[string]struct{}, len(batch.Stations))
+
+	for i, st := range batch.Stations {
+		id := strings.TrimSpace(st.Id)
+		switch {
+		case len(id) == 0:
+			fail("stations", i, "id is empty")
+			continue
+		case st.Latitude == nil || st.Longitude == nil:
+			fail("stations", i, "latitude and longitude are required")
+			continue
+		case *st.Latitude < -90 || *st.Latitude > 90 || *st.Longitude < -180 || *st.Longitude > 180:
+			fail("stations", i, "position is out of range")
+			continue
+		}
+		if _, dup := seen[id]; dup {
+			fail("stations", i, fmt.Sprintf("duplicate id %s", id))
+			continue
+		}
+		seen[id] = struct{}{}
+
+		trackers = append(trackers, models.Tracker{
+			OrigId:      id,
+			Source:      source,
+			Description: st.Title,
+			Latitude:    *st.Latitude,
+			Longitude:   *st.Longitude,
+		})
+	}
+
+	measurements := make([]models.Measurement, 0, len(batch.Readings))
+	// readings from a skewed clock of the partner are accepted up to the tolerance
+	latest := h.now().Add(5 * time.Minute)
+
+	for i, rd := range batch.Readings {
+		// matches the trimmed ids of the stations
+		stationId := strings.TrimSpace(rd.StationId)
+		switch {
+		case len(rd.Pollutant) == 0:
+			fail("readings", i, "pollutant is empty")
+			continue
+		case rd.Value < 0:
+			fail("readings", i, "value is negative")
+			continue
+		case rd.ObservedAt.IsZero():
+			fail("readings", i, "observed_at is required")
+			continue
+		case rd.ObservedAt.After(latest):
+			fail("readings", i, "observed_at is in the future")
+			continue
+		}
+		if _, exists := seen[stationId]; !exists {
+			fail("readings", i, fmt.Sprintf("unknown station %q", rd.StationId))
+			continue
+		}
+
+		unit := rd.Unit
+		if len(unit) == 0 {
+			unit = models.UnitMicrogramsPerCubicMeter
+		}
+
+		tr := models.Tracker{Source: source, OrigId: stationId}
+		measurements = append(measurements, models.Measurement{
+			TrackerId:  tr.Id(),
+			Pollutant:  models.Pollutant(strings.ToLower(rd.Pollutant)),
+			Value:      rd.Value,
+			Unit:       unit,
+			ObservedAt: rd.ObservedAt.UTC(),
+		})
+	}
+
+	return trackers, measurements, errs
+}
+
+func authorized(r *http.Request, token string) bool {
+	got, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || len(token) == 0 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,219 @@
+package httpapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MRibalko/smogtracker/pkg/logger/slogdiscard"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/push"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/httpapi"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/services/trackerlist"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+type testIngester struct {
+	source       models.SourceName
+	trackers     []models.Tracker
+	measurements []models.Measurement
+}
+
+func (ti *testIngester) Ingest(ctx context.Context,
+	source models.SourceName,
+	trackers []models.Tracker,
+	measurements []models.Measurement,
+) error {
+	ti.source = source
+	ti.trackers = trackers
+	ti.measurements = measurements
+	return nil
+}
+
+func TestIngest(t *testing.T) {
+	const (
+		url   = "/v1/sources/partner/batch"
+		token = "secret"
+	)
+
+	newServer := func(t *testing.T) (*http.ServeMux, *testIngester) {
+		t.Helper()
+		ingester := &testIngester{}
+		mux := http.NewServeMux()
+		httpapi.RegisterIngest(mux, slogdiscard.NewDiscardLogger(), ingester, map[string]string{"partner": token})
+		return mux, ingester
+	}
+
+	post := func(mux *http.ServeMux, url, token, body string) (*httptest.ResponseRecorder, httpapi.IngestResponse) {
+		req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		if len(token) != 0 {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		var resp httpapi.IngestResponse
+		_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec, resp
+	}
+
+	t.Run("Valid batch", func(t *testing.T) {
+		mux, ingester := newServer(t)
+
+		rec, resp := post(mux, url, token, `{
+			"stations": [
+				{"id": "1", "title": "Kentron", "latitude": 40.182, "longitude": 44.516},
+				{"id": "2", "title": "Nor Nork", "latitude": 40.2, "longitude": 44.582}
+			],
+			"readings": [
+				{"station_id": "1", "pollutant": "PM25", "value": 15, "observed_at": "2024-03-08T10:37:33Z"}
+			]
+		}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, httpapi.IngestResponse{Stations: 2, Readings: 1}, resp)
+
+		assert.Equal(t, models.SourceName("partner"), ingester.source)
+		assert.Equal(t, []models.Tracker{
+			{OrigId: "1", Source: "partner", Description: "Kentron", Latitude: 40.182, Longitude: 44.516},
+			{OrigId: "2", Source: "partner", Description: "Nor Nork", Latitude: 40.2, Longitude: 44.582},
+		}, ingester.trackers)
+		assert.Equal(t, []models.Measurement{{
+			TrackerId:  "partner|1",
+			Pollutant:  models.PM25,
+			Value:      15,
+			Unit:       models.UnitMicrogramsPerCubicMeter,
+			ObservedAt: time.Date(2024, 3, 8, 10, 37, 33, 0, time.UTC),
+		}}, ingester.measurements)
+	})
+
+	t.Run("Ids are trimmed", func(t *testing.T) {
+		mux, ingester := newServer(t)
+
+		rec, resp := post(mux, url, token, `{
+			"stations": [{"id": " 1 ", "latitude": 40.182, "longitude": 44.516}],
+			"readings": [
+				{"station_id": "1", "pollutant": "pm25", "value": 15, "observed_at": "2024-03-08T10:37:33Z"},
+				{"station_id": "1 ", "pollutant": "pm10", "value": 20, "observed_at": "2024-03-08T10:37:33Z"}
+			]
+		}`)
+		require.Equal(t, http.StatusOK, rec.Code, resp.Errors)
+
+		require.Len(t, ingester.trackers, 1)
+		assert.Equal(t, "1", ingester.trackers[0].OrigId)
+		require.Len(t, ingester.measurements, 2)
+		for _, m := range ingester.measurements {
+			assert.Equal(t, ingester.trackers[0].Id(), m.TrackerId)
+		}
+	})
+
+	t.Run("Per item errors", func(t *testing.T) {
+		mux, ingester := newServer(t)
+
+		rec, resp := post(mux, url, token, `{
+			"stations": [
+				{"id": "1", "latitude": 40.182, "longitude": 44.516},
+				{"id": "", "latitude": 40.2, "longitude": 44.582},
+				{"id": "3", "latitude": 140.2, "longitude": 44.582},
+				{"id": "1", "latitude": 40.2, "longitude": 44.582},
+				{"id": "5", "latitude": 40.2}
+			],
+			"readings": [
+				{"station_id": "9", "pollutant": "pm25", "value": 15, "observed_at": "2024-03-08T10:37:33Z"},
+				{"station_id": "1", "pollutant": "pm25", "value": -1, "observed_at": "2024-03-08T10:37:33Z"},
+				{"station_id": "1", "pollutant": "pm25", "value": 1}
+			]
+		}`)
+		require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+		var items []string
+		for _, e := range resp.Errors {
+			items = append(items, e.Item)
+		}
+		assert.Equal(t, []string{
+			"stations[1]", "stations[2]", "stations[3]", "stations[4]",
+			"readings[0]", "readings[1]", "readings[2]",
+		}, items)
+		assert.Nil(t, ingester.trackers, "invalid batch isn't ingested")
+	})
+
+	t.Run("Authentication", func(t *testing.T) {
+		mux, ingester := newServer(t)
+		body := `{"stations": [{"id": "1", "latitude": 40.182, "longitude": 44.516}]}`
+
+		rec, _ := post(mux, url, "", body)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		rec, _ = post(mux, url, "wrong", body)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		rec, _ = post(mux, "/v1/sources/other/batch", token, body)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		assert.Nil(t, ingester.trackers)
+	})
+
+	t.Run("Malformed batch", func(t *testing.T) {
+		mux, _ := newServer(t)
+
+		rec, _ := post(mux, url, token, `{"stations": [`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec, _ = post(mux, url, token, `{"station": []}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "unknown fields are rejected")
+	})
+}
+
+func TestIngest_DeletionGuard(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.New(otel.Tracer("test"))
+
+	tl, err := trackerlist.New(slogdiscard.NewDiscardLogger(), otel.Tracer("test"), otel.Meter("test"), storage)
+	require.NoError(t, err)
+	require.NoError(t, tl.RegisterSource(push.New("partner")))
+	require.NoError(t, tl.SetDeletionGuard("partner", trackerlist.DeletionGuard{MaxCount: 1}))
+
+	mux := http.NewServeMux()
+	httpapi.RegisterIngest(mux, slogdiscard.NewDiscardLogger(), tl, map[string]string{"partner": "secret"})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/sources/partner/batch", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := post(`{"stations": [
+		{"id": "1", "latitude": 40.1, "longitude": 44.5},
+		{"id": "2", "latitude": 40.2, "longitude": 44.5},
+		{"id": "3", "latitude": 40.3, "longitude": 44.5}
+	]}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// replaces the three stations with another one
+	rec = post(`{
+		"stations": [{"id": "4", "latitude": 40.4, "longitude": 44.5}],
+		"readings": [{"station_id": "4", "pollutant": "pm25", "value": 15, "observed_at": "2024-03-08T10:37:33Z"}]
+	}`)
+	assert.Equal(t, http.StatusConflict, rec.Code, "the pusher is told nothing was applied")
+
+	ids, err := tl.IdsBySource(ctx, "partner")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"1", "2", "3"}, ids)
+
+	readings, err := storage.Measurements(ctx, models.Id("partner|4"), time.Time{}, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, readings, "readings of the refused batch aren't stored")
+
+	pending, err := tl.PendingRefreshes(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, 3, pending[0].Deletes)
+}
@@ -0,0 +1,193 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Compiled JSONPath-like expression. Supported syntax:
+	// an optional leading $, .name, ['name'], [index] and the [*] or .* wildcard
+	Path []segment
+
+	segment struct {
+		key      string
+		index    int
+		isIndex  bool
+		wildcard bool
+	}
+)
+
+// Compiles the expression, an empty expression or $ selects the document itself
+func Parse(expr string) (Path, error) {
+	var path Path
+
+	rest := strings.TrimPrefix(strings.TrimSpace(expr), "$")
+	for len(rest) != 0 {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+
+			switch name {
+			case "":
+				return nil, fmt.Errorf("path %q: empty name", expr)
+			case "*":
+				path = append(path, segment{wildcard: true})
+			default:
+				path = append(path, segment{key: name})
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("path %q: unclosed bracket", expr)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			seg, err := bracketSegment(inner)
+			if err != nil {
+				return nil, fmt.Errorf("path %q: %w", expr, err)
+			}
+			path = append(path, seg)
+		default:
+			// the leading name may omit the dot
+			if len(path) != 0 {
+				return nil, fmt.Errorf("path %q: unexpected %q", expr, rest[0])
+			}
+			rest = "." + rest
+		}
+	}
+
+	return path, nil
+}
+
+func bracketSegment(inner string) (segment, error) {
+	if inner == "*" {
+		return segment{wildcard: true}, nil
+	}
+
+	if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+		return segment{key: inner[1 : len(inner)-1]}, nil
+	}
+
+	index, err := strconv.Atoi(inner)
+	if err != nil {
+		return segment{}, fmt.Errorf("bad index %q", inner)
+	}
+	return segment{index: index, isIndex: true}, nil
+}
+
+// Returns every value the path selects in the decoded JSON document.
+// Negative indexes count from the end of an array
+func (p Path) Eval(doc any) []any {
+	values := []any{doc}
+
+	for _, seg := range p {
+		var next []any
+		for _, v := range values {
+			next = append(next, seg.apply(v)...)
+		}
+		values = next
+	}
+
+	return values
+}
+
+// Returns the first value the path selects
+func (p Path) First(doc any) (any, bool) {
+	values := p.Eval(doc)
+	if len(values) == 0 {
+		return nil, false
+	}
+	return values[0], true
+}
+
+func (s segment) apply(v any) []any {
+	switch node := v.(type) {
+	case map[string]any:
+		if s.wildcard {
+			// members are selected in the order of their names to keep results stable
+			keys := make([]string, 0, len(node))
+			for k := range node {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			res := make([]any, 0, len(node))
+			for _, k := range keys {
+				res = append(res, node[k])
+			}
+			return res
+		}
+		if s.isIndex {
+			return nil
+		}
+		if child, ok := node[s.key]; ok {
+			return []any{child}
+		}
+	case []any:
+		if s.wildcard {
+			return node
+		}
+		if !s.isIndex {
+			return nil
+		}
+		i := s.index
+		if i < 0 {
+			i += len(node)
+		}
+		if i >= 0 && i < len(node) {
+			return []any{node[i]}
+		}
+	}
+	return nil
+}
+
+// Returns the first selected value as a string, numbers are formatted as written
+// if the document is decoded with json.Decoder.UseNumber
+func (p Path) Text(doc any) (string, error) {
+	v, ok := p.First(doc)
+	if !ok {
+		return "", errors.New("not found")
+	}
+
+	switch val := v.(type) {
+	case string:
+		return val, nil
+	case json.Number:
+		return val.String(), nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	}
+	return "", fmt.Errorf("unexpected type %T", v)
+}
+
+// Returns the first selected value as a number, numeric strings are accepted
+func (p Path) Number(doc any) (float64, error) {
+	v, ok := p.First(doc)
+	if !ok {
+		return 0, errors.New("not found")
+	}
+
+	switch val := v.(type) {
+	case json.Number:
+		return val.Float64()
+	case float64:
+		return val, nil
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(val), 64)
+	}
+	return 0, fmt.Errorf("unexpected type %T", v)
+}
@@ -0,0 +1,64 @@
+package jsonpath_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/jsonpath"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath_Eval(t *testing.T) {
+	const doc = `{
+		"data": {
+			"regions": [
+				{"stations": [{"id": 1}, {"id": 2}]},
+				{"stations": [{"id": 3}]}
+			]
+		},
+		"station name": "Kentron",
+		"coords": [40.182, 44.516]
+	}`
+
+	var decoded any
+	require.NoError(t, json.Unmarshal([]byte(doc), &decoded))
+
+	cases := []struct {
+		expr string
+		want []any
+	}{
+		{"$.station name", []any{"Kentron"}},
+		{"['station name']", []any{"Kentron"}},
+		{"coords[0]", []any{40.182}},
+		{"$.coords[-1]", []any{44.516}},
+		{"coords[2]", nil},
+		{"$.data.regions[*].stations[*].id", []any{1.0, 2.0, 3.0}},
+		{"data.regions[1].stations[0].id", []any{3.0}},
+		{"$.missing.id", nil},
+		{"coords.id", nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expr, func(t *testing.T) {
+			p, err := jsonpath.Parse(tc.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, p.Eval(decoded))
+		})
+	}
+
+	t.Run("document itself", func(t *testing.T) {
+		p, err := jsonpath.Parse("$")
+		require.NoError(t, err)
+		assert.Equal(t, []any{decoded}, p.Eval(decoded))
+	})
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, expr := range []string{"$.", "a..b", "a[0", "a[x]", "a[0]b"} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := jsonpath.Parse(expr)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package models
+
+// Changes of a source refresh which are applied to the storage at once
+type Changeset struct {
+	Inserts []Tracker
+	Updates []Tracker
+	Deletes []Id
+}
+
+func (c Changeset) Len() int {
+	return len(c.Inserts) + len(c.Updates) + len(c.Deletes)
+}
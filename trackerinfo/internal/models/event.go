@@ -0,0 +1,20 @@
+package models
+
+type (
+	ChangeType string
+
+	// Change of a tracker applied to the storage.
+	// Tracker.Revision holds the revision assigned to the change
+	Event struct {
+		Type    ChangeType
+		Tracker Tracker
+	}
+)
+
+const (
+	ChangeInserted ChangeType = "inserted"
+	ChangeUpdated  ChangeType = "updated"
+	ChangeDeleted  ChangeType = "deleted"
+	// update moving the tracker farther than the jitter threshold of its source
+	ChangeRelocated ChangeType = "relocated"
+)
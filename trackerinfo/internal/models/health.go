@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type (
+	SourceState string
+
+	// Fetch history of a source
+	SourceHealth struct {
+		Source SourceName
+		State  SourceState
+		// zero if the source has never been fetched successfully
+		LastSuccess time.Time
+		LastError   string
+		LastErrorAt time.Time
+		// failed fetches since the last successful one
+		ConsecutiveFailures int
+		// next fetch attempt of a source with the open circuit
+		RetryAt time.Time
+	}
+)
+
+const (
+	SourceHealthy SourceState = "healthy"
+	// the last fetches failed but the source is still polled
+	SourceDegraded SourceState = "degraded"
+	// fetches are suspended for the cool-down period
+	SourceOpenCircuit SourceState = "open_circuit"
+)
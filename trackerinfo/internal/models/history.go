@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type (
+	VersionKind string
+
+	// State of a tracker which was valid within [ValidFrom, ValidTo).
+	// ValidTo is zero for the current version
+	TrackerVersion struct {
+		Tracker   Tracker
+		Kind      VersionKind
+		ValidFrom time.Time
+		ValidTo   time.Time
+	}
+)
+
+const (
+	// the tracker appeared in its source or came back after removal
+	VersionCreated VersionKind = "created"
+	// coordinates changed, readings before and after the change come from different places
+	VersionMoved   VersionKind = "moved"
+	VersionRenamed VersionKind = "renamed"
+	VersionRemoved VersionKind = "removed"
+)
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type (
+	Pollutant string
+
+	Measurement struct {
+		TrackerId  Id
+		Pollutant  Pollutant
+		Value      float64
+		Unit       string
+		ObservedAt time.Time
+	}
+)
+
+const (
+	PM25 Pollutant = "pm25"
+	PM10 Pollutant = "pm10"
+
+	UnitMicrogramsPerCubicMeter = "µg/m³"
+)
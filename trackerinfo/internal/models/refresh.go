@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Source refresh refused by the deletion guard.
+// It is kept until an operator confirms or discards it, or the next refresh replaces it
+type PendingRefresh struct {
+	Source SourceName
+	// trackers of the refused feed
+	Trackers []Tracker
+	// number of trackers of the source before the refresh
+	Current   int
+	Deletes   int
+	RefusedAt time.Time
+}
@@ -1,14 +1,18 @@
 package models
 
 import (
-	"crypto/md5"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/geo"
 )
 
 type (
-	Hash       string
 	Id         string
 	SourceName string
+	// Number of the last change of a tracker, grows with every insert, update and delete
+	Revision int64
 
 	Tracker struct {
 		OrigId      string
@@ -16,15 +20,38 @@ type (
 		Description string
 		Latitude    float64
 		Longitude   float64
+		// zero unless the tracker has been removed from its source
+		DeletedAt time.Time
+		Revision  Revision
+	}
+
+	// Tracker found by a geospatial query with its distance in meters to the queried point
+	NearTracker struct {
+		Tracker  Tracker
+		Distance float64
+	}
+
+	// Difference between two versions of a tracker
+	Diff struct {
+		Description bool
+		// meters between the positions of the versions
+		Distance float64
 	}
 )
 
-// returns MD5 hash of fields Description, Latitude, Longitude
-func (t *Tracker) Hash() Hash {
+// Compares fields Description, Latitude, Longitude with the other version of the tracker
+func (t *Tracker) Diff(other Tracker) Diff {
+	d := Diff{Description: t.Description != other.Description}
+	if t.Latitude != other.Latitude || t.Longitude != other.Longitude {
+		d.Distance = geo.Distance(
+			geo.Point{Latitude: t.Latitude, Longitude: t.Longitude},
+			geo.Point{Latitude: other.Latitude, Longitude: other.Longitude})
+	}
+	return d
+}
 
-	h := md5.New()
-	fmt.Fprintf(h, "%s|%f|%f", t.Description, t.Latitude, t.Longitude)
-	return Hash(h.Sum(nil))
+func (d Diff) Moved() bool {
+	return d.Distance > 0
 }
 
 func (t *Tracker) Id() Id {
@@ -34,3 +61,13 @@ func (t *Tracker) Id() Id {
 func (t *Tracker) SourceName() SourceName {
 	return SourceName(t.Source)
 }
+
+func (t *Tracker) IsDeleted() bool {
+	return !t.DeletedAt.IsZero()
+}
+
+// Splits the id back into a tracker with Source and OrigId set
+func (id Id) Tracker() Tracker {
+	source, origId, _ := strings.Cut(string(id), "|")
+	return Tracker{Source: source, OrigId: origId}
+}
@@ -0,0 +1,161 @@
+package trackerlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	ErrNoPendingRefresh = errors.New("no pending refresh")
+	// The refresh is refused by the deletion guard and kept until an operator confirms it
+	ErrRefreshPending = errors.New("refresh is pending confirmation")
+)
+
+// Limits the number of trackers a single refresh of a source may delete.
+// Zero fields are not checked
+type DeletionGuard struct {
+	// share of the source trackers in percent
+	MaxPercent float64
+	MaxCount   int
+}
+
+// Reports whether deleting deletes of current trackers exceeds the limits
+func (g DeletionGuard) Exceeded(current, deletes int) bool {
+	if g.MaxCount > 0 && deletes > g.MaxCount {
+		return true
+	}
+	if g.MaxPercent > 0 && current > 0 && float64(deletes)*100 > g.MaxPercent*float64(current) {
+		return true
+	}
+	return false
+}
+
+// Sets the deletion guard of a registered source
+func (tl *TrackerList) SetDeletionGuard(source models.SourceName, guard DeletionGuard) error {
+	const op = "TrackerList.SetDeletionGuard"
+
+	if guard.MaxPercent < 0 || guard.MaxPercent > 100 || guard.MaxCount < 0 {
+		return fmt.Errorf("%s: guard limits are out of range", op)
+	}
+
+	if _, exists := tl.sources[source]; !exists {
+		return fmt.Errorf("%s: source %s is not registered", op, source)
+	}
+
+	tl.mu.Lock()
+	tl.guards[source] = guard
+	tl.mu.Unlock()
+
+	return nil
+}
+
+// Returns refreshes refused by the deletion guards ordered by source
+func (tl *TrackerList) PendingRefreshes(ctx context.Context) ([]models.PendingRefresh, error) {
+	const op = "TrackerList.PendingRefreshes"
+	_, span := tl.tracer.Start(ctx, op)
+	defer span.End()
+
+	tl.mu.Lock()
+	res := make([]models.PendingRefresh, 0, len(tl.pending))
+	for _, p := range tl.pending {
+		res = append(res, p)
+	}
+	tl.mu.Unlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Source < res[j].Source
+	})
+
+	span.SetAttributes(attribute.Int("refreshes returned", len(res)))
+
+	return res, nil
+}
+
+// Applies the refused refresh of the source bypassing its deletion guard
+func (tl *TrackerList) ConfirmRefresh(ctx context.Context, source models.SourceName) error {
+	const op = "TrackerList.ConfirmRefresh"
+	ctx, span := tl.tracer.Start(ctx, op)
+	defer span.End()
+
+	lock, exists := tl.refreshLocks[source]
+	if !exists {
+		return fmt.Errorf("%s: %s: %w", op, source, ErrNoPendingRefresh)
+	}
+	// the source isn't refreshed by its fetcher meanwhile
+	lock.Lock()
+	defer lock.Unlock()
+
+	tl.mu.Lock()
+	pending, exists := tl.pending[source]
+	tl.mu.Unlock()
+	if !exists {
+		return fmt.Errorf("%s: %s: %w", op, source, ErrNoPendingRefresh)
+	}
+
+	if err := tl.refresh(ctx, source, pending.Trackers, false); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tl.log.Warn("refused refresh confirmed",
+		slog.String("op", op),
+		slog.String("source", string(source)),
+		slog.Int("deletes", pending.Deletes))
+
+	return nil
+}
+
+// Drops the refused refresh of the source.
+// The next refresh is checked by the deletion guard again
+func (tl *TrackerList) DiscardRefresh(ctx context.Context, source models.SourceName) error {
+	const op = "TrackerList.DiscardRefresh"
+	_, span := tl.tracer.Start(ctx, op)
+	defer span.End()
+
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	if _, exists := tl.pending[source]; !exists {
+		return fmt.Errorf("%s: %s: %w", op, source, ErrNoPendingRefresh)
+	}
+	delete(tl.pending, source)
+
+	tl.log.Info("refused refresh discarded",
+		slog.String("op", op),
+		slog.String("source", string(source)))
+
+	return nil
+}
+
+// Keeps the refresh as pending if the guard of the source refuses it
+func (tl *TrackerList) guardRefresh(ctx context.Context,
+	source models.SourceName,
+	updates []models.Tracker,
+	current, deletes int,
+) bool {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	if !tl.guards[source].Exceeded(current, deletes) {
+		return false
+	}
+
+	tl.pending[source] = models.PendingRefresh{
+		Source:    source,
+		Trackers:  updates,
+		Current:   current,
+		Deletes:   deletes,
+		RefusedAt: time.Now().UTC(),
+	}
+	tl.metrics.refusedRefreshes.Add(ctx, 1,
+		metric.WithAttributes(attribute.String("source", string(source))))
+
+	return true
+}
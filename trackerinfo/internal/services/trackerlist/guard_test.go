@@ -0,0 +1,114 @@
+package trackerlist_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/services/trackerlist"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeletionGuard_Exceeded(t *testing.T) {
+	cases := []struct {
+		name     string
+		guard    trackerlist.DeletionGuard
+		current  int
+		deletes  int
+		exceeded bool
+	}{
+		{"no limits", trackerlist.DeletionGuard{}, 10, 10, false},
+		{"count within", trackerlist.DeletionGuard{MaxCount: 3}, 10, 3, false},
+		{"count over", trackerlist.DeletionGuard{MaxCount: 3}, 10, 4, true},
+		{"percent within", trackerlist.DeletionGuard{MaxPercent: 50}, 10, 5, false},
+		{"percent over", trackerlist.DeletionGuard{MaxPercent: 50}, 10, 6, true},
+		{"empty source", trackerlist.DeletionGuard{MaxPercent: 50}, 0, 0, false},
+		{"either limit", trackerlist.DeletionGuard{MaxPercent: 90, MaxCount: 2}, 10, 3, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.exceeded, tc.guard.Exceeded(tc.current, tc.deletes))
+		})
+	}
+}
+
+func TestTrackerList_DeletionGuard(t *testing.T) {
+	ctx := context.Background()
+
+	var stored []models.Tracker
+	for i := range 10 {
+		stored = append(stored, models.Tracker{OrigId: fmt.Sprint(i), Source: "source1"})
+	}
+
+	// the feed is truncated to a single tracker
+	newTrackerList := func(t *testing.T) (*trackerlist.TrackerList, *testStorage) {
+		t.Helper()
+		storage := &testStorage{trackers: stored}
+
+		tl, err := newTrackerListWithStorage(t, storage)
+		require.NoError(t, err)
+
+		err = tl.RegisterSource(&testFetcher{
+			data:     stored[:1],
+			name:     "source1",
+			interval: 10 * time.Second,
+		})
+		require.NoError(t, err)
+		require.NoError(t, tl.SetDeletionGuard("source1", trackerlist.DeletionGuard{MaxPercent: 50}))
+
+		tl.StartUpdate(ctx)
+		time.Sleep(150 * time.Millisecond)
+		tl.StopUpdate()
+
+		return tl, storage
+	}
+
+	t.Run("Refused and confirmed", func(t *testing.T) {
+		t.Parallel()
+		tl, storage := newTrackerList(t)
+		assert.Equal(t, 0, storage.deleted, "refused refresh is not applied")
+
+		pending, err := tl.PendingRefreshes(ctx)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, models.SourceName("source1"), pending[0].Source)
+		assert.Equal(t, 10, pending[0].Current)
+		assert.Equal(t, 9, pending[0].Deletes)
+		assert.Len(t, pending[0].Trackers, 1)
+
+		require.NoError(t, tl.ConfirmRefresh(ctx, "source1"))
+		assert.Equal(t, 9, storage.deleted)
+
+		pending, err = tl.PendingRefreshes(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+
+		require.ErrorIs(t, tl.ConfirmRefresh(ctx, "source1"), trackerlist.ErrNoPendingRefresh)
+	})
+
+	t.Run("Refused and discarded", func(t *testing.T) {
+		t.Parallel()
+		tl, storage := newTrackerList(t)
+
+		require.NoError(t, tl.DiscardRefresh(ctx, "source1"))
+		assert.Equal(t, 0, storage.deleted)
+
+		pending, err := tl.PendingRefreshes(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+
+		require.ErrorIs(t, tl.DiscardRefresh(ctx, "source1"), trackerlist.ErrNoPendingRefresh)
+	})
+
+	t.Run("Guard of unknown source", func(t *testing.T) {
+		t.Parallel()
+		tl, err := newTrackerListWithStorage(t, &testStorage{})
+		require.NoError(t, err)
+
+		require.Error(t, tl.SetDeletionGuard("unknown", trackerlist.DeletionGuard{MaxCount: 1}))
+	})
+}
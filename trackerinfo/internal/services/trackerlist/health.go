@@ -0,0 +1,181 @@
+package trackerlist
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Applied to every registered source until SetCircuitBreaker replaces it
+var DefaultCircuitBreaker = CircuitBreaker{
+	FailureThreshold: 5,
+	CoolDown:         30 * time.Minute,
+}
+
+// Suspends fetches of a source failing FailureThreshold times in a row.
+// After CoolDown a single fetch is attempted, the circuit is closed if it succeeds.
+// Zero FailureThreshold never opens the circuit
+type CircuitBreaker struct {
+	FailureThreshold int
+	CoolDown         time.Duration
+}
+
+type sourceHealth struct {
+	models.SourceHealth
+	breaker CircuitBreaker
+}
+
+// Sets the circuit breaker of a registered source
+func (tl *TrackerList) SetCircuitBreaker(source models.SourceName, breaker CircuitBreaker) error {
+	const op = "TrackerList.SetCircuitBreaker"
+
+	if breaker.FailureThreshold < 0 || breaker.CoolDown < 0 ||
+		(breaker.FailureThreshold > 0 && breaker.CoolDown == 0) {
+		return fmt.Errorf("%s: breaker limits are out of range", op)
+	}
+
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	h, exists := tl.health[source]
+	if !exists {
+		return fmt.Errorf("%s: source %s is not registered", op, source)
+	}
+	h.breaker = breaker
+
+	return nil
+}
+
+// Returns the health of the source or of all the sources ordered by name if source is empty
+func (tl *TrackerList) SourceStatus(ctx context.Context, source models.SourceName) ([]models.SourceHealth, error) {
+	const op = "TrackerList.SourceStatus"
+	_, span := tl.tracer.Start(ctx, op)
+	defer span.End()
+
+	tl.mu.Lock()
+	res := make([]models.SourceHealth, 0, len(tl.health))
+	for name, h := range tl.health {
+		if len(source) == 0 || name == source {
+			res = append(res, h.SourceHealth)
+		}
+	}
+	tl.mu.Unlock()
+
+	if len(source) != 0 && len(res) == 0 {
+		return nil, fmt.Errorf("%s: %s: %w", op, source, storage.ErrSourceNotFound)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Source < res[j].Source
+	})
+
+	span.SetAttributes(attribute.Int("sources returned", len(res)))
+
+	return res, nil
+}
+
+// Reports whether the source may be fetched now. The open circuit lets a single fetch through
+// once the cool-down is over, it is reopened if that fetch fails
+func (tl *TrackerList) allowFetch(source models.SourceName) bool {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	h := tl.health[source]
+	return h.State != models.SourceOpenCircuit || !time.Now().Before(h.RetryAt)
+}
+
+func (tl *TrackerList) fetchSucceeded(source models.SourceName) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	h := tl.health[source]
+	if h.State == models.SourceOpenCircuit {
+		tl.log.Info("source circuit closed", slog.String("source", string(source)))
+	}
+
+	h.State = models.SourceHealthy
+	h.LastSuccess = time.Now().UTC()
+	h.ConsecutiveFailures = 0
+	h.RetryAt = time.Time{}
+}
+
+func (tl *TrackerList) fetchFailed(ctx context.Context, source models.SourceName, err error) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	h := tl.health[source]
+	h.LastError = err.Error()
+	h.LastErrorAt = time.Now().UTC()
+	h.ConsecutiveFailures++
+
+	if h.breaker.FailureThreshold == 0 || h.ConsecutiveFailures < h.breaker.FailureThreshold {
+		h.State = models.SourceDegraded
+		return
+	}
+
+	h.RetryAt = h.LastErrorAt.Add(h.breaker.CoolDown)
+	if h.State != models.SourceOpenCircuit {
+		h.State = models.SourceOpenCircuit
+		tl.metrics.openedCircuits.Add(ctx, 1,
+			metric.WithAttributes(attribute.String("source", string(source))))
+	}
+
+	tl.log.Warn("source circuit open",
+		slog.String("source", string(source)),
+		slog.Int("failures", h.ConsecutiveFailures),
+		slog.Time("retry_at", h.RetryAt))
+}
+
+// Gauge values of the source states
+var sourceStates = map[models.SourceState]int64{
+	models.SourceHealthy:     0,
+	models.SourceDegraded:    1,
+	models.SourceOpenCircuit: 2,
+}
+
+// Reports the health of the sources on every metrics collection
+func (tl *TrackerList) observeHealth(meter metric.Meter) error {
+	state, err := meter.Int64ObservableGauge("sourceState",
+		metric.WithDescription("State of the source: 0 healthy, 1 degraded, 2 open circuit"))
+	if err != nil {
+		return err
+	}
+
+	failures, err := meter.Int64ObservableGauge("sourceConsecutiveFailures",
+		metric.WithDescription("Number of failed fetches of the source since the last successful one"),
+		metric.WithUnit("{fetch}"))
+	if err != nil {
+		return err
+	}
+
+	lastSuccess, err := meter.Int64ObservableGauge("sourceLastSuccess",
+		metric.WithDescription("Unix time of the last successful fetch of the source"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		tl.mu.Lock()
+		defer tl.mu.Unlock()
+
+		for name, h := range tl.health {
+			attrs := metric.WithAttributes(attribute.String("source", string(name)))
+			o.ObserveInt64(state, sourceStates[h.State], attrs)
+			o.ObserveInt64(failures, int64(h.ConsecutiveFailures), attrs)
+			if !h.LastSuccess.IsZero() {
+				o.ObserveInt64(lastSuccess, h.LastSuccess.Unix(), attrs)
+			}
+		}
+		return nil
+	}, state, failures, lastSuccess)
+
+	return err
+}
@@ -0,0 +1,118 @@
+package trackerlist_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/services/trackerlist"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Fails the first failures fetches
+type failingFetcher struct {
+	testFetcher
+	failures int32
+	calls    atomic.Int32
+}
+
+func (f *failingFetcher) Fetch(ctx context.Context) ([]models.Tracker, error) {
+	if f.calls.Add(1) <= f.failures {
+		return nil, errors.New("connection refused")
+	}
+	return f.data, nil
+}
+
+func TestTrackerList_SourceStatus(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(t *testing.T, f *failingFetcher, breaker trackerlist.CircuitBreaker) *trackerlist.TrackerList {
+		t.Helper()
+		tl, err := newTrackerListWithStorage(t, &testStorage{})
+		require.NoError(t, err)
+
+		require.NoError(t, tl.RegisterSource(f))
+		require.NoError(t, tl.SetCircuitBreaker(f.Name(), breaker))
+
+		tl.StartUpdate(ctx)
+		time.Sleep(150 * time.Millisecond)
+		tl.StopUpdate()
+
+		return tl
+	}
+
+	t.Run("Healthy", func(t *testing.T) {
+		t.Parallel()
+		f := &failingFetcher{testFetcher: testFetcher{
+			data:     []models.Tracker{{OrigId: "1", Source: "source1"}},
+			name:     "source1",
+			interval: 10 * time.Second,
+		}}
+		tl := run(t, f, trackerlist.DefaultCircuitBreaker)
+
+		status, err := tl.SourceStatus(ctx, "source1")
+		require.NoError(t, err)
+		require.Len(t, status, 1)
+		assert.Equal(t, models.SourceHealthy, status[0].State)
+		assert.False(t, status[0].LastSuccess.IsZero())
+		assert.Zero(t, status[0].ConsecutiveFailures)
+	})
+
+	t.Run("Degraded", func(t *testing.T) {
+		t.Parallel()
+		f := &failingFetcher{failures: 100, testFetcher: testFetcher{name: "source1", interval: 20 * time.Millisecond}}
+		tl := run(t, f, trackerlist.CircuitBreaker{FailureThreshold: 100, CoolDown: time.Hour})
+
+		status, err := tl.SourceStatus(ctx, "")
+		require.NoError(t, err)
+		require.Len(t, status, 1)
+		assert.Equal(t, models.SourceDegraded, status[0].State)
+		assert.Equal(t, "connection refused", status[0].LastError)
+		assert.Equal(t, int(f.calls.Load()), status[0].ConsecutiveFailures)
+		assert.True(t, status[0].LastSuccess.IsZero())
+	})
+
+	t.Run("Open circuit", func(t *testing.T) {
+		t.Parallel()
+		f := &failingFetcher{failures: 100, testFetcher: testFetcher{name: "source1", interval: 10 * time.Millisecond}}
+		tl := run(t, f, trackerlist.CircuitBreaker{FailureThreshold: 2, CoolDown: time.Hour})
+
+		assert.Equal(t, int32(2), f.calls.Load(), "fetches are suspended after the threshold")
+
+		status, err := tl.SourceStatus(ctx, "source1")
+		require.NoError(t, err)
+		assert.Equal(t, models.SourceOpenCircuit, status[0].State)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), status[0].RetryAt, time.Minute)
+	})
+
+	t.Run("Closed after cool-down", func(t *testing.T) {
+		t.Parallel()
+		f := &failingFetcher{failures: 2, testFetcher: testFetcher{
+			data:     []models.Tracker{{OrigId: "1", Source: "source1"}},
+			name:     "source1",
+			interval: 10 * time.Millisecond,
+		}}
+		tl := run(t, f, trackerlist.CircuitBreaker{FailureThreshold: 2, CoolDown: 30 * time.Millisecond})
+
+		status, err := tl.SourceStatus(ctx, "source1")
+		require.NoError(t, err)
+		assert.Equal(t, models.SourceHealthy, status[0].State)
+		assert.True(t, status[0].RetryAt.IsZero())
+		assert.Greater(t, f.calls.Load(), int32(2))
+	})
+
+	t.Run("Unknown source", func(t *testing.T) {
+		t.Parallel()
+		tl, err := newTrackerListWithStorage(t, &testStorage{})
+		require.NoError(t, err)
+
+		_, err = tl.SourceStatus(ctx, "unknown")
+		require.ErrorIs(t, err, storage.ErrSourceNotFound)
+		require.Error(t, tl.SetCircuitBreaker("unknown", trackerlist.DefaultCircuitBreaker))
+	})
+}
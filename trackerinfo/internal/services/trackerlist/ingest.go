@@ -0,0 +1,78 @@
+package trackerlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var ErrNotPassive = errors.New("source is not passive")
+
+// Applies a batch pushed to a passive source. The trackers replace the current trackers
+// of the source the same way a fetched feed does, the readings are stored after them.
+// Returns ErrRefreshPending and stores nothing if the deletion guard refuses the batch
+func (tl *TrackerList) Ingest(ctx context.Context,
+	source models.SourceName,
+	trackers []models.Tracker,
+	measurements []models.Measurement,
+) error {
+	const op = "TrackerList.Ingest"
+	ctx, span := tl.tracer.Start(ctx, op)
+	defer span.End()
+
+	span.SetAttributes(attribute.String("source", string(source)),
+		attribute.Int("trackers", len(trackers)),
+		attribute.Int("readings", len(measurements)))
+
+	fetcher, exists := tl.sources[source]
+	if !exists {
+		span.SetStatus(codes.Error, "source not found")
+		return fmt.Errorf("%s: %s: %w", op, source, storage.ErrSourceNotFound)
+	}
+	if !isPassive(fetcher) {
+		span.SetStatus(codes.Error, "source is not passive")
+		return fmt.Errorf("%s: %s: %w", op, source, ErrNotPassive)
+	}
+
+	for _, tr := range trackers {
+		if tr.SourceName() != source {
+			span.SetStatus(codes.Error, "tracker of another source")
+			return fmt.Errorf("%s: tracker %s doesn't belong to %s", op, tr.Id(), source)
+		}
+	}
+	for _, m := range measurements {
+		if tr := m.TrackerId.Tracker(); tr.SourceName() != source {
+			span.SetStatus(codes.Error, "reading of another source")
+			return fmt.Errorf("%s: reading of %s doesn't belong to %s", op, m.TrackerId, source)
+		}
+	}
+
+	if err := tl.makeUpdates(ctx, source, trackers); err != nil {
+		span.SetStatus(codes.Error, "update failed")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	// a push tells the source is alive the same way a fetch does
+	tl.fetchSucceeded(source)
+
+	if len(measurements) == 0 {
+		return nil
+	}
+
+	if err := tl.storage.AddMeasurements(ctx, measurements); err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	tl.metrics.storedMeasurements.Add(ctx, int64(len(measurements)))
+
+	return nil
+}
+
+func isPassive(f Fetcher) bool {
+	p, ok := as[PassiveFetcher](f)
+	return ok && p.Passive()
+}
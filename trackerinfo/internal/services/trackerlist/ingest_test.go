@@ -0,0 +1,56 @@
+package trackerlist_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/fetchers/push"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/services/trackerlist"
+	errStorage "github.com/MRibalko/smogtracker/trackerinfo/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackerList_Ingest(t *testing.T) {
+	ctx := context.Background()
+
+	stored := models.Tracker{OrigId: "1", Source: "partner", Description: "1", Latitude: 1, Longitude: 1}
+	pushed := models.Tracker{OrigId: "2", Source: "partner", Description: "2", Latitude: 2, Longitude: 2}
+
+	storage := &testStorage{trackers: []models.Tracker{stored}}
+
+	tl, err := newTrackerListWithStorage(t, storage)
+	require.NoError(t, err)
+
+	require.NoError(t, tl.RegisterSource(push.New("partner")), "passive sources have no update interval")
+	require.NoError(t, tl.RegisterSource(&testFetcher{name: "polled", interval: 10 * time.Second}))
+
+	tl.StartUpdate(ctx)
+	time.Sleep(150 * time.Millisecond)
+	tl.StopUpdate()
+	assert.Equal(t, 0, storage.deleted, "passive source isn't polled")
+
+	measurements := []models.Measurement{{
+		TrackerId:  pushed.Id(),
+		Pollutant:  models.PM25,
+		Value:      15,
+		Unit:       models.UnitMicrogramsPerCubicMeter,
+		ObservedAt: time.Date(2024, 3, 8, 10, 37, 33, 0, time.UTC),
+	}}
+
+	require.NoError(t, tl.Ingest(ctx, "partner", []models.Tracker{pushed}, measurements))
+	assert.Equal(t, 1, storage.inserted)
+	assert.Equal(t, 1, storage.deleted, "the batch replaces the trackers of the source")
+	assert.Equal(t, measurements, storage.measurements)
+
+	err = tl.Ingest(ctx, "polled", []models.Tracker{{OrigId: "1", Source: "polled"}}, nil)
+	require.ErrorIs(t, err, trackerlist.ErrNotPassive)
+
+	err = tl.Ingest(ctx, "unknown", []models.Tracker{pushed}, nil)
+	require.ErrorIs(t, err, errStorage.ErrSourceNotFound)
+
+	err = tl.Ingest(ctx, "partner", []models.Tracker{{OrigId: "1", Source: "polled"}}, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package trackerlist
+
+import (
+	"fmt"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+)
+
+// How the fetched version of a known tracker differs from the stored one
+type changeKind int
+
+const (
+	changeNone changeKind = iota
+	// the coordinates moved within the jitter threshold, the description is the same
+	changeJitter
+	changeDescription
+	changeRelocation
+)
+
+// Sets the distance in meters the trackers of a registered source may wobble by
+// without being moved. Zero makes every coordinate change a relocation
+func (tl *TrackerList) SetJitterThreshold(source models.SourceName, meters float64) error {
+	const op = "TrackerList.SetJitterThreshold"
+
+	if meters < 0 {
+		return fmt.Errorf("%s: threshold must not be negative", op)
+	}
+
+	if _, exists := tl.sources[source]; !exists {
+		return fmt.Errorf("%s: source %s is not registered", op, source)
+	}
+
+	tl.mu.Lock()
+	tl.jitter[source] = meters
+	tl.mu.Unlock()
+
+	return nil
+}
+
+// Classifies the change of the tracker. Coordinates are compared with the stored ones,
+// so a slow drift becomes a relocation once it exceeds the threshold
+func classify(stored, fetched models.Tracker, threshold float64) changeKind {
+	diff := stored.Diff(fetched)
+
+	switch {
+	case diff.Moved() && diff.Distance > threshold:
+		return changeRelocation
+	case diff.Description:
+		return changeDescription
+	case diff.Moved():
+		return changeJitter
+	}
+	return changeNone
+}
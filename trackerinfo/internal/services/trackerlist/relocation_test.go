@@ -0,0 +1,103 @@
+package trackerlist_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackerList_Relocation(t *testing.T) {
+	stored := models.Tracker{
+		OrigId:      "76921",
+		Source:      "armaqi",
+		Description: "Kentron",
+		Latitude:    40.182,
+		Longitude:   44.516,
+	}
+
+	cases := []struct {
+		name    string
+		fetched models.Tracker
+		// expected event, empty if nothing is stored
+		changeType models.ChangeType
+		want       models.Tracker
+	}{
+		{
+			name:    "unchanged",
+			fetched: stored,
+		},
+		{
+			name:    "jitter ignored",
+			fetched: models.Tracker{OrigId: "76921", Source: "armaqi", Description: "Kentron", Latitude: 40.183, Longitude: 44.515},
+		},
+		{
+			name:       "description keeps stored coordinates",
+			fetched:    models.Tracker{OrigId: "76921", Source: "armaqi", Description: "Kentron 2", Latitude: 40.183, Longitude: 44.515},
+			changeType: models.ChangeUpdated,
+			want:       models.Tracker{OrigId: "76921", Source: "armaqi", Description: "Kentron 2", Latitude: 40.182, Longitude: 44.516},
+		},
+		{
+			name:       "relocation",
+			fetched:    models.Tracker{OrigId: "76921", Source: "armaqi", Description: "Kentron", Latitude: 40.2, Longitude: 44.516},
+			changeType: models.ChangeRelocated,
+			want:       models.Tracker{OrigId: "76921", Source: "armaqi", Description: "Kentron", Latitude: 40.2, Longitude: 44.516},
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			storage := &testStorage{trackers: []models.Tracker{stored}}
+
+			tl, err := newTrackerListWithStorage(t, storage)
+			require.NoError(t, err)
+
+			require.NoError(t, tl.RegisterSource(&testFetcher{
+				data:     []models.Tracker{tc.fetched},
+				name:     "armaqi",
+				interval: 10 * time.Second,
+			}))
+			require.NoError(t, tl.SetJitterThreshold("armaqi", 250))
+
+			events, err := tl.Watch(ctx, "armaqi", 0)
+			require.NoError(t, err)
+
+			tl.StartUpdate(ctx)
+			time.Sleep(150 * time.Millisecond)
+			tl.StopUpdate()
+
+			var got []models.Event
+			for ev := range events {
+				got = append(got, ev)
+			}
+
+			if len(tc.changeType) == 0 {
+				assert.Equal(t, 0, storage.updated, "no updates expected")
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.Equal(t, 1, storage.updated, "1 update expected")
+			require.Len(t, got, 1)
+			assert.Equal(t, tc.changeType, got[0].Type)
+
+			got[0].Tracker.Revision = 0
+			assert.Equal(t, tc.want, got[0].Tracker)
+		})
+	}
+
+	t.Run("Negative threshold", func(t *testing.T) {
+		tl, err := newTrackerListWithStorage(t, &testStorage{})
+		require.NoError(t, err)
+		require.NoError(t, tl.RegisterSource(&testFetcher{name: "armaqi", interval: 10 * time.Second}))
+
+		require.Error(t, tl.SetJitterThreshold("armaqi", -1))
+		require.Error(t, tl.SetJitterThreshold("unknown", 100))
+	})
+}
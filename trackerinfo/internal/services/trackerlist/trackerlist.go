@@ -5,11 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"strings"
 	"sync"
 	"time"
 
 	sl "github.com/MRibalko/smogtracker/pkg/logger"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/geo"
 	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -19,13 +19,18 @@ import (
 
 type (
 	Storage interface {
-		Insert(ctx context.Context, tracker models.Tracker) error
-		Update(ctx context.Context, tracker models.Tracker) error
-		Delete(ctx context.Context, id models.Id) error
+		// Applies all the changes or none of them, returns the applied ones ordered by revision
+		Apply(ctx context.Context, changes models.Changeset) ([]models.Event, error)
 		Trackers(ctx context.Context) ([]models.Tracker, error)
 		ModifiedTrackers(ctx context.Context, modifiedFrom time.Time) ([]models.Tracker, error)
+		ChangedTrackers(ctx context.Context, after models.Revision) ([]models.Tracker, error)
+		History(ctx context.Context, id models.Id) ([]models.TrackerVersion, error)
 		Sources(ctx context.Context) ([]string, error)
 		IdsBySource(ctx context.Context, source string) ([]string, error)
+		AddMeasurements(ctx context.Context, measurements []models.Measurement) error
+		Measurements(ctx context.Context, id models.Id, from, to time.Time) ([]models.Measurement, error)
+		Nearest(ctx context.Context, point geo.Point, radius float64, limit int) ([]models.NearTracker, error)
+		TrackersInBounds(ctx context.Context, bounds geo.Bounds, sources []string) ([]models.Tracker, error)
 	}
 
 	Fetcher interface {
@@ -34,6 +39,30 @@ type (
 		UpdateInterval() time.Duration
 	}
 
+	// Optional interface of a Fetcher whose trackers are pushed with TrackerList.Ingest.
+	// Passive fetchers aren't polled and need no update interval
+	PassiveFetcher interface {
+		Passive() bool
+	}
+
+	// Optional interface of a Fetcher which is able to provide pollutant readings
+	// for the trackers it has fetched
+	MeasurementFetcher interface {
+		FetchMeasurements(ctx context.Context, trackers []models.Tracker) ([]models.Measurement, error)
+	}
+
+	// Optional interface of a Fetcher whose feed may be empty.
+	// An empty feed deletes all the trackers of the source unless the deletion guard refuses it
+	EmptyFeedFetcher interface {
+		AllowsEmptyFeed() bool
+	}
+
+	// Fetcher decorating another one, e.g. with retries.
+	// The optional interfaces are looked up through the chain of wrapped fetchers
+	WrappingFetcher interface {
+		Unwrap() Fetcher
+	}
+
 	TrackerList struct {
 		log     *slog.Logger
 		tracer  trace.Tracer
@@ -41,14 +70,32 @@ type (
 		storage Storage
 		sources map[models.SourceName]Fetcher
 		mu      sync.Mutex
-		hashes  map[models.SourceName]map[models.Id]models.Hash
+		// stored versions of the trackers of each source
+		cache   map[models.SourceName]map[models.Id]models.Tracker
 		cancel  context.CancelFunc
 		running bool
+
+		// refreshes of a source are applied one at a time
+		refreshLocks map[models.SourceName]*sync.Mutex
+		// changes of all sources are applied and published one changeset at a time,
+		// so the watchers get the events in revision order
+		applyMu sync.Mutex
+		guards       map[models.SourceName]DeletionGuard
+		pending      map[models.SourceName]models.PendingRefresh
+		jitter       map[models.SourceName]float64
+		health       map[models.SourceName]*sourceHealth
+
+		subMu       sync.Mutex
+		subscribers map[*subscriber]struct{}
 	}
 
 	instruments struct {
-		writeDbRequests metric.Int64Counter
-		cacheRequests   metric.Int64Counter
+		writeDbRequests    metric.Int64Counter
+		cacheRequests      metric.Int64Counter
+		storedMeasurements metric.Int64Counter
+		refusedRefreshes   metric.Int64Counter
+		relocations        metric.Int64Counter
+		openedCircuits     metric.Int64Counter
 	}
 )
 
@@ -65,7 +112,19 @@ func New(logger *slog.Logger, tracer trace.Tracer, meter metric.Meter, storage S
 		metrics: metrics,
 		storage: storage,
 		sources: make(map[models.SourceName]Fetcher),
-		hashes:  make(map[models.SourceName]map[models.Id]models.Hash),
+		cache:   make(map[models.SourceName]map[models.Id]models.Tracker),
+
+		refreshLocks: make(map[models.SourceName]*sync.Mutex),
+		guards:       make(map[models.SourceName]DeletionGuard),
+		pending:      make(map[models.SourceName]models.PendingRefresh),
+		jitter:       make(map[models.SourceName]float64),
+		health:       make(map[models.SourceName]*sourceHealth),
+
+		subscribers: make(map[*subscriber]struct{}),
+	}
+
+	if err := tl.observeHealth(meter); err != nil {
+		return nil, err
 	}
 
 	trList, err := tl.storage.Trackers(context.Background())
@@ -74,11 +133,11 @@ func New(logger *slog.Logger, tracer trace.Tracer, meter metric.Meter, storage S
 	}
 
 	for _, tr := range trList {
-		if _, exists := tl.hashes[tr.SourceName()]; !exists {
-			tl.hashes[tr.SourceName()] = make(map[models.Id]models.Hash)
+		if _, exists := tl.cache[tr.SourceName()]; !exists {
+			tl.cache[tr.SourceName()] = make(map[models.Id]models.Tracker)
 		}
 
-		tl.hashes[tr.SourceName()][tr.Id()] = tr.Hash()
+		tl.cache[tr.SourceName()][tr.Id()] = tr
 	}
 
 	return tl, nil
@@ -95,7 +154,7 @@ func (tl *TrackerList) RegisterSource(source Fetcher) error {
 		return fmt.Errorf("%s: name is empty", op)
 	}
 
-	if source.UpdateInterval() == 0 {
+	if source.UpdateInterval() == 0 && !isPassive(source) {
 		return fmt.Errorf("%s: update interval must be positive", op)
 	}
 
@@ -109,8 +168,15 @@ func (tl *TrackerList) RegisterSource(source Fetcher) error {
 
 	log.Info(fmt.Sprintf("adding source %s", source.Name()))
 	tl.sources[source.Name()] = source
-	if _, exists := tl.hashes[source.Name()]; !exists {
-		tl.hashes[models.SourceName(source.Name())] = make(map[models.Id]models.Hash)
+	tl.refreshLocks[source.Name()] = &sync.Mutex{}
+	tl.mu.Lock()
+	tl.health[source.Name()] = &sourceHealth{
+		SourceHealth: models.SourceHealth{Source: source.Name(), State: models.SourceHealthy},
+		breaker:      DefaultCircuitBreaker,
+	}
+	tl.mu.Unlock()
+	if _, exists := tl.cache[source.Name()]; !exists {
+		tl.cache[source.Name()] = make(map[models.Id]models.Tracker)
 	}
 	return nil
 }
@@ -129,6 +195,10 @@ func (tl *TrackerList) StartUpdate(ctx context.Context) {
 	var wg sync.WaitGroup
 
 	for _, v := range tl.sources {
+		if isPassive(v) {
+			log.Info(fmt.Sprintf("fetcher \"%s\" is passive, waiting for pushes", v.Name()))
+			continue
+		}
 		wg.Add(1)
 		go func() {
 			log.Info(fmt.Sprintf("fetcher \"%s\" started, update interval %s", v.Name(), v.UpdateInterval()))
@@ -138,12 +208,27 @@ func (tl *TrackerList) StartUpdate(ctx context.Context) {
 
 			for {
 
-				res, err := v.Fetch(updctx)
-				if err != nil {
+				// a failed fetch says nothing about the trackers of the source,
+				// they are kept until the next successful one
+				if !tl.allowFetch(v.Name()) {
+					log.Debug(fmt.Sprintf("fetch \"%s\" skipped, circuit is open", v.Name()))
+				} else if res, err := v.Fetch(updctx); err != nil {
+					if updctx.Err() == nil {
+						tl.fetchFailed(updctx, v.Name(), err)
+					}
 					log.Error(fmt.Sprintf("fetch \"%s\" failed", v.Name()), sl.Err(err))
-				}
-				if err := tl.makeUpdates(updctx, v.Name(), res); err != nil {
-					log.Error(fmt.Sprintf("update \"%s\" failed", v.Name()), sl.Err(err))
+				} else {
+					tl.fetchSucceeded(v.Name())
+					// readings of a refused feed may belong to trackers which aren't stored
+					err := tl.makeUpdates(updctx, v.Name(), res)
+					if err != nil && !errors.Is(err, ErrRefreshPending) {
+						log.Error(fmt.Sprintf("update \"%s\" failed", v.Name()), sl.Err(err))
+					}
+					if mf, ok := as[MeasurementFetcher](v); ok && len(res) != 0 && !errors.Is(err, ErrRefreshPending) {
+						if err := tl.updateMeasurements(updctx, mf, res); err != nil {
+							log.Error(fmt.Sprintf("measurements update \"%s\" failed", v.Name()), sl.Err(err))
+						}
+					}
 				}
 
 				select {
@@ -170,6 +255,7 @@ func (tl *TrackerList) StopUpdate() {
 	log := tl.log.With(slog.String("op", op))
 	log.Info("Trackers update stopping")
 	tl.cancel()
+	tl.closeSubscribers()
 }
 
 // Returns the list of added data sources
@@ -230,6 +316,8 @@ func (tl *TrackerList) List(ctx context.Context) ([]models.Tracker, error) {
 	return list, nil
 }
 
+// Returns the list of trackers modified since modifiedFrom.
+// Trackers removed from their sources are returned with DeletedAt set
 func (tl *TrackerList) ListSince(ctx context.Context, modifiedFrom time.Time) ([]models.Tracker, error) {
 	const op = "TrackerList.ListSince"
 	ctx, span := tl.tracer.Start(ctx, op)
@@ -245,68 +333,304 @@ func (tl *TrackerList) ListSince(ctx context.Context, modifiedFrom time.Time) ([
 	return list, nil
 }
 
+// Returns the list of trackers changed after the revision ordered by revision.
+// Trackers removed from their sources are returned with DeletedAt set
+func (tl *TrackerList) ListAfter(ctx context.Context, revision models.Revision) ([]models.Tracker, error) {
+	const op = "TrackerList.ListAfter"
+	ctx, span := tl.tracer.Start(ctx, op)
+	defer span.End()
+
+	list, err := tl.storage.ChangedTrackers(ctx, revision)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	span.SetAttributes(attribute.Int("trackers returned", len(list)))
+
+	return list, nil
+}
+
+// Returns every version of the tracker ordered by revision
+func (tl *TrackerList) History(ctx context.Context, source, origId string) ([]models.TrackerVersion, error) {
+	const op = "TrackerList.History"
+	ctx, span := tl.tracer.Start(ctx, op)
+	defer span.End()
+
+	id := (&models.Tracker{Source: source, OrigId: origId}).Id()
+	span.SetAttributes(attribute.String("trackerId", string(id)))
+
+	list, err := tl.storage.History(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	span.SetAttributes(attribute.Int("versions returned", len(list)))
+
+	return list, nil
+}
+
+// Returns up to limit trackers within radius meters of the point ordered by distance
+func (tl *TrackerList) Nearest(ctx context.Context, point geo.Point, radius float64, limit int) ([]models.NearTracker, error) {
+	const op = "TrackerList.Nearest"
+	ctx, span := tl.tracer.Start(ctx, op)
+	defer span.End()
+
+	if point.Latitude < -90 || point.Latitude > 90 || point.Longitude < -180 || point.Longitude > 180 {
+		span.SetStatus(codes.Error, "point is out of range")
+		return nil, fmt.Errorf("%s: point is out of range", op)
+	}
+
+	if radius <= 0 || limit <= 0 {
+		span.SetStatus(codes.Error, "radius and limit must be positive")
+		return nil, fmt.Errorf("%s: radius and limit must be positive", op)
+	}
+
+	list, err := tl.storage.Nearest(ctx, point, radius, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	span.SetAttributes(attribute.Int("trackers returned", len(list)))
+
+	return list, nil
+}
+
+// Returns trackers inside the rectangle. The rectangle crosses the antimeridian if West is greater than East.
+// Returns trackers of all sources if sources are empty
+func (tl *TrackerList) ListInBounds(ctx context.Context, bounds geo.Bounds, sources []string) ([]models.Tracker, error) {
+	const op = "TrackerList.ListInBounds"
+	ctx, span := tl.tracer.Start(ctx, op)
+	defer span.End()
+
+	if bounds.South > bounds.North || bounds.South < -90 || bounds.North > 90 ||
+		bounds.West < -180 || bounds.West > 180 || bounds.East < -180 || bounds.East > 180 {
+		span.SetStatus(codes.Error, "bounds are not valid")
+		return nil, fmt.Errorf("%s: bounds are not valid", op)
+	}
+
+	list, err := tl.storage.TrackersInBounds(ctx, bounds, sources)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	span.SetAttributes(attribute.Int("trackers returned", len(list)))
+
+	return list, nil
+}
+
+// Returns readings of the tracker observed within [from, to) ordered by observation time.
+// Returns readings of all pollutants if pollutant is empty
+func (tl *TrackerList) Readings(ctx context.Context,
+	source, origId string,
+	pollutant models.Pollutant,
+	from, to time.Time,
+) ([]models.Measurement, error) {
+	const op = "TrackerList.Readings"
+	ctx, span := tl.tracer.Start(ctx, op)
+	defer span.End()
+
+	id := (&models.Tracker{Source: source, OrigId: origId}).Id()
+	span.SetAttributes(attribute.String("trackerId", string(id)))
+
+	list, err := tl.storage.Measurements(ctx, id, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(pollutant) != 0 {
+		filtered := list[:0]
+		for _, m := range list {
+			if m.Pollutant == pollutant {
+				filtered = append(filtered, m)
+			}
+		}
+		list = filtered
+	}
+
+	span.SetAttributes(attribute.Int("readings returned", len(list)))
+
+	return list, nil
+}
+
 func (tl *TrackerList) makeUpdates(ctx context.Context, source models.SourceName, updates []models.Tracker) error {
 	const op = "TrackerList.makeUpdates"
-	log := tl.log.With(slog.String("op", op))
+
+	lock, exists := tl.refreshLocks[source]
+	if !exists {
+		return fmt.Errorf("%s: source %s is not registered", op, source)
+	}
+
 	if len(updates) == 0 {
-		return errors.New("updates slice is empty")
+		if ef, ok := as[EmptyFeedFetcher](tl.sources[source]); !ok || !ef.AllowsEmptyFeed() {
+			return errors.New("updates slice is empty")
+		}
 	}
+	lock.Lock()
+	defer lock.Unlock()
+
+	return tl.refresh(ctx, source, updates, true)
+}
+
+// Applies the fetched feed of the source. The caller holds the refresh lock of the source
+func (tl *TrackerList) refresh(ctx context.Context, source models.SourceName, updates []models.Tracker, guarded bool) error {
+	const op = "TrackerList.refresh"
+	log := tl.log.With(slog.String("op", op))
 
 	log.Info(fmt.Sprintf("Updating source %s", source))
 
 	tl.mu.Lock()
-	hashes, exists := tl.hashes[source]
+	cached, exists := tl.cache[source]
+	threshold := tl.jitter[source]
 	tl.mu.Unlock()
 	if !exists {
-		return fmt.Errorf("%s: no hash for source %s", op, source)
+		return fmt.Errorf("%s: no cache for source %s", op, source)
 	}
-	updHashes := make(map[models.Id]models.Hash)
+	updCache := make(map[models.Id]models.Tracker, len(updates))
+	relocated := make(map[models.Id]struct{})
+	jittered, duplicates := 0, 0
+	var changes models.Changeset
 
 	for _, tr := range updates {
+		// a repeated id would fail the whole changeset, the first occurrence wins
+		if _, dup := updCache[tr.Id()]; dup {
+			duplicates++
+			continue
+		}
 
 		tl.metrics.cacheRequests.Add(ctx, 1)
 
-		trHash, exist := hashes[tr.Id()]
-
+		stored, exist := cached[tr.Id()]
 		if !exist {
-			tl.metrics.writeDbRequests.Add(ctx, 1)
-
-			err := tl.storage.Insert(ctx, tr)
-			if err != nil {
-				log.Error("tracker insertion failed", slog.String("SourceId", string(tr.Id())), sl.Err(err))
-				return err
-			}
+			changes.Inserts = append(changes.Inserts, tr)
+			updCache[tr.Id()] = tr
+			continue
 		}
 
-		if exist && strings.Compare(string(trHash), string(tr.Hash())) != 0 {
-			tl.metrics.writeDbRequests.Add(ctx, 1)
+		switch classify(stored, tr, threshold) {
+		case changeRelocation:
+			relocated[tr.Id()] = struct{}{}
+			changes.Updates = append(changes.Updates, tr)
+		case changeDescription:
+			// the jitter of the position isn't stored along with the new description
+			tr.Latitude, tr.Longitude = stored.Latitude, stored.Longitude
+			changes.Updates = append(changes.Updates, tr)
+		case changeJitter:
+			jittered++
+			tr = stored
+		case changeNone:
+			tr = stored
+		}
+		updCache[tr.Id()] = tr // mark that the tracker exists in updated feed
+	}
 
-			err := tl.storage.Update(ctx, tr)
-			if err != nil {
-				log.Error("tracker update failed", slog.String("Id", string(tr.Id())), sl.Err(err))
-				return err
-			}
+	// trackers missing in the updated feed
+	for id := range cached {
+		if _, exists := updCache[id]; !exists {
+			changes.Deletes = append(changes.Deletes, id)
 		}
-		updHashes[tr.Id()] = tr.Hash() // mark that the tracker exists in updated feed
-		delete(hashes, tr.Id())        // delete the tracker from stale hashes
+	}
 
+	if guarded && tl.guardRefresh(ctx, source, updates, len(cached), len(changes.Deletes)) {
+		log.Warn("refresh refused by the deletion guard",
+			slog.String("source", string(source)),
+			slog.Int("current", len(cached)),
+			slog.Int("fetched", len(updates)),
+			slog.Int("deletes", len(changes.Deletes)))
+		return fmt.Errorf("%s: %s: %w", op, source, ErrRefreshPending)
 	}
 
-	for id := range hashes {
-		if err := tl.storage.Delete(ctx, id); err != nil {
-			log.Error("tracker deletion failed", slog.String("Id", string(id)), sl.Err(err))
-			return err
+	if changes.Len() != 0 {
+		tl.metrics.writeDbRequests.Add(ctx, 1)
+
+		if err := tl.applyAndPublish(ctx, changes, relocated); err != nil {
+			log.Error("changes apply failed", slog.Int("changes", changes.Len()), sl.Err(err))
+			return fmt.Errorf("%s: %w", op, err)
 		}
 	}
 
+	if len(relocated) != 0 {
+		tl.metrics.relocations.Add(ctx, int64(len(relocated)),
+			metric.WithAttributes(attribute.String("source", string(source))))
+		log.Info("trackers relocated", slog.Int("count", len(relocated)))
+	}
+	if duplicates != 0 {
+		log.Warn("repeated trackers of the feed skipped", slog.Int("count", duplicates))
+	}
+	if jittered != 0 {
+		log.Debug("jitter of coordinates ignored", slog.Int("count", jittered))
+	}
+
+	// the cache follows the storage only after the changes are applied
 	tl.mu.Lock()
-	tl.hashes[source] = updHashes
+	tl.cache[source] = updCache
+	// an applied refresh supersedes the refused one
+	delete(tl.pending, source)
 	tl.mu.Unlock()
 
 	log.Info("trackers updated")
 	return nil
 }
 
+// Publishes the events of the changeset before the next one is applied
+func (tl *TrackerList) applyAndPublish(ctx context.Context, changes models.Changeset, relocated map[models.Id]struct{}) error {
+	tl.applyMu.Lock()
+	defer tl.applyMu.Unlock()
+
+	events, err := tl.storage.Apply(ctx, changes)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range events {
+		if _, ok := relocated[e.Tracker.Id()]; ok && e.Type == models.ChangeUpdated {
+			e.Type = models.ChangeRelocated
+		}
+		tl.publish(e)
+	}
+	return nil
+}
+
+// Fetches readings of the trackers and stores them.
+// Readings received along with a fetch error are stored as well
+func (tl *TrackerList) updateMeasurements(ctx context.Context, fetcher MeasurementFetcher, trackers []models.Tracker) error {
+	const op = "TrackerList.updateMeasurements"
+	log := tl.log.With(slog.String("op", op))
+
+	measurements, fetchErr := fetcher.FetchMeasurements(ctx, trackers)
+	if fetchErr != nil {
+		fetchErr = fmt.Errorf("%s: %w", op, fetchErr)
+	}
+
+	if len(measurements) == 0 {
+		return fetchErr
+	}
+
+	if err := tl.storage.AddMeasurements(ctx, measurements); err != nil {
+		return errors.Join(fetchErr, fmt.Errorf("%s: %w", op, err))
+	}
+	tl.metrics.storedMeasurements.Add(ctx, int64(len(measurements)))
+
+	log.Info(fmt.Sprintf("%d measurements stored", len(measurements)))
+	return fetchErr
+}
+
+// Finds the first fetcher implementing T in the chain of wrapped fetchers
+func as[T any](f Fetcher) (T, bool) {
+	for f != nil {
+		if t, ok := f.(T); ok {
+			return t, true
+		}
+		w, ok := f.(WrappingFetcher)
+		if !ok {
+			break
+		}
+		f = w.Unwrap()
+	}
+
+	var zero T
+	return zero, false
+}
+
 func newInstruments(meter metric.Meter) (*instruments, error) {
 	writeDbRequests, err := meter.Int64Counter("writeDbRequests",
 		metric.WithDescription("Number of write requests to db"),
@@ -322,9 +646,41 @@ func newInstruments(meter metric.Meter) (*instruments, error) {
 		return nil, err
 	}
 
+	storedMeasurements, err := meter.Int64Counter("storedMeasurements",
+		metric.WithDescription("Number of measurements written to db"),
+		metric.WithUnit("{measurement}"))
+	if err != nil {
+		return nil, err
+	}
+
+	refusedRefreshes, err := meter.Int64Counter("refusedRefreshes",
+		metric.WithDescription("Number of source refreshes refused by the deletion guard"),
+		metric.WithUnit("{refresh}"))
+	if err != nil {
+		return nil, err
+	}
+
+	relocations, err := meter.Int64Counter("relocations",
+		metric.WithDescription("Number of trackers moved farther than the jitter threshold of their source"),
+		metric.WithUnit("{tracker}"))
+	if err != nil {
+		return nil, err
+	}
+
+	openedCircuits, err := meter.Int64Counter("openedCircuits",
+		metric.WithDescription("Number of times fetches of a source were suspended after repeated failures"),
+		metric.WithUnit("{circuit}"))
+	if err != nil {
+		return nil, err
+	}
+
 	return &instruments{
-		writeDbRequests: writeDbRequests,
-		cacheRequests:   cacheRequests,
+		writeDbRequests:    writeDbRequests,
+		cacheRequests:      cacheRequests,
+		storedMeasurements: storedMeasurements,
+		refusedRefreshes:   refusedRefreshes,
+		relocations:        relocations,
+		openedCircuits:     openedCircuits,
 	}, nil
 
 }
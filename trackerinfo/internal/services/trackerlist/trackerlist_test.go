@@ -2,39 +2,62 @@ package trackerlist_test
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/MRibalko/smogtracker/pkg/logger/slogdiscard"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/geo"
 	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
 	"github.com/MRibalko/smogtracker/trackerinfo/internal/services/trackerlist"
+	errStorage "github.com/MRibalko/smogtracker/trackerinfo/internal/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
 )
 
 type testStorage struct {
-	trackers []models.Tracker
-	sources  []string
-	ids      []string
-	inserted int
-	updated  int
-	deleted  int
+	trackers     []models.Tracker
+	sources      []string
+	ids          []string
+	inserted     int
+	updated      int
+	deleted      int
+	measurements []models.Measurement
+	// number of Apply calls failing before the first successful one
+	applyFailures int
 }
 
-func (ts *testStorage) Insert(ctx context.Context, tracker models.Tracker) error {
-	ts.inserted++
-	return nil
-}
+func (ts *testStorage) Apply(ctx context.Context, changes models.Changeset) ([]models.Event, error) {
+	if ts.applyFailures > 0 {
+		ts.applyFailures--
+		return nil, errors.New("apply failed")
+	}
 
-func (ts *testStorage) Update(ctx context.Context, tracker models.Tracker) error {
-	ts.updated++
-	return nil
+	var events []models.Event
+	for _, tr := range changes.Inserts {
+		ts.inserted++
+		tr.Revision = ts.revision()
+		events = append(events, models.Event{Type: models.ChangeInserted, Tracker: tr})
+	}
+	for _, tr := range changes.Updates {
+		ts.updated++
+		tr.Revision = ts.revision()
+		events = append(events, models.Event{Type: models.ChangeUpdated, Tracker: tr})
+	}
+	for _, id := range changes.Deletes {
+		ts.deleted++
+		tr := id.Tracker()
+		tr.Revision = ts.revision()
+		tr.DeletedAt = time.Now()
+		events = append(events, models.Event{Type: models.ChangeDeleted, Tracker: tr})
+	}
+	return events, nil
 }
 
-func (ts *testStorage) Delete(ctx context.Context, id models.Id) error {
-	ts.deleted++
-	return nil
+func (ts *testStorage) revision() models.Revision {
+	return models.Revision(ts.inserted + ts.updated + ts.deleted)
 }
 
 func (ts *testStorage) Trackers(ctx context.Context) ([]models.Tracker, error) {
@@ -45,6 +68,14 @@ func (ts *testStorage) ModifiedTrackers(ctx context.Context, from time.Time) ([]
 	return ts.trackers, nil
 }
 
+func (ts *testStorage) ChangedTrackers(ctx context.Context, after models.Revision) ([]models.Tracker, error) {
+	return ts.trackers, nil
+}
+
+func (ts *testStorage) History(ctx context.Context, id models.Id) ([]models.TrackerVersion, error) {
+	return nil, nil
+}
+
 func (ts *testStorage) Sources(ctx context.Context) ([]string, error) {
 	return ts.sources, nil
 }
@@ -53,14 +84,32 @@ func (ts *testStorage) IdsBySource(ctx context.Context, source string) ([]string
 	return ts.ids, nil
 }
 
+func (ts *testStorage) AddMeasurements(ctx context.Context, measurements []models.Measurement) error {
+	ts.measurements = append(ts.measurements, measurements...)
+	return nil
+}
+
+func (ts *testStorage) Measurements(ctx context.Context, id models.Id, from, to time.Time) ([]models.Measurement, error) {
+	return ts.measurements, nil
+}
+
+func (ts *testStorage) Nearest(ctx context.Context, point geo.Point, radius float64, limit int) ([]models.NearTracker, error) {
+	return nil, nil
+}
+
+func (ts *testStorage) TrackersInBounds(ctx context.Context, bounds geo.Bounds, sources []string) ([]models.Tracker, error) {
+	return ts.trackers, nil
+}
+
 type testFetcher struct {
 	data     []models.Tracker
+	err      error
 	name     string
 	interval time.Duration
 }
 
 func (tf *testFetcher) Fetch(ctx context.Context) ([]models.Tracker, error) {
-	return tf.data, nil
+	return tf.data, tf.err
 }
 
 func (tf *testFetcher) Name() models.SourceName {
@@ -71,6 +120,45 @@ func (tf *testFetcher) UpdateInterval() time.Duration {
 	return tf.interval
 }
 
+type testMeasurementFetcher struct {
+	testFetcher
+	measurements []models.Measurement
+	err          error
+}
+
+func (tf *testMeasurementFetcher) FetchMeasurements(ctx context.Context, trackers []models.Tracker) ([]models.Measurement, error) {
+	return tf.measurements, tf.err
+}
+
+type testEmptyFeedFetcher struct {
+	testFetcher
+}
+
+func (tf *testEmptyFeedFetcher) AllowsEmptyFeed() bool {
+	return true
+}
+
+// Hides the optional interfaces of the wrapped fetcher
+type testWrappingFetcher struct {
+	fetcher trackerlist.Fetcher
+}
+
+func (tf *testWrappingFetcher) Fetch(ctx context.Context) ([]models.Tracker, error) {
+	return tf.fetcher.Fetch(ctx)
+}
+
+func (tf *testWrappingFetcher) Name() models.SourceName {
+	return tf.fetcher.Name()
+}
+
+func (tf *testWrappingFetcher) UpdateInterval() time.Duration {
+	return tf.fetcher.UpdateInterval()
+}
+
+func (tf *testWrappingFetcher) Unwrap() trackerlist.Fetcher {
+	return tf.fetcher
+}
+
 func TestTrackerList_RegisterSource(t *testing.T) {
 	cases := []struct {
 		name        string
@@ -278,6 +366,196 @@ func TestTrackerList_Update(t *testing.T) {
 		assert.Equal(t, 1, storage.updated, "1 update expected")
 	})
 
+	t.Run("Failed apply is retried", func(t *testing.T) {
+		t.Parallel()
+		storage := &testStorage{applyFailures: 1}
+
+		tl, err := newTrackerListWithStorage(t, storage)
+		require.NoError(t, err)
+
+		err = tl.RegisterSource(&testFetcher{
+			data:     []models.Tracker{testTracker1},
+			name:     "source1",
+			interval: 20 * time.Millisecond,
+		})
+		require.NoError(t, err)
+
+		tl.StartUpdate(ctx)
+		time.Sleep(150 * time.Millisecond)
+		tl.StopUpdate()
+		assert.Equal(t, 1, storage.inserted, "the cache keeps the tracker unknown until it is stored")
+		assert.Equal(t, 0, storage.deleted, "no deletions expected")
+		assert.Equal(t, 0, storage.updated, "no updates expected")
+	})
+
+	t.Run("Repeated ids", func(t *testing.T) {
+		t.Parallel()
+		storage := &testStorage{}
+
+		tl, err := newTrackerListWithStorage(t, storage)
+		require.NoError(t, err)
+
+		repeated := testTracker1
+		repeated.Description = "repeated"
+		err = tl.RegisterSource(&testFetcher{
+			data:     []models.Tracker{testTracker1, repeated},
+			name:     "source1",
+			interval: 10 * time.Second,
+		})
+		require.NoError(t, err)
+
+		tl.StartUpdate(ctx)
+		time.Sleep(150 * time.Millisecond)
+		tl.StopUpdate()
+		assert.Equal(t, 1, storage.inserted, "the first occurrence is inserted")
+		assert.Equal(t, 0, storage.updated, "no updates expected")
+	})
+
+	t.Run("Empty feed", func(t *testing.T) {
+		t.Parallel()
+		storage := &testStorage{trackers: []models.Tracker{testTracker1}}
+
+		tl, err := newTrackerListWithStorage(t, storage)
+		require.NoError(t, err)
+
+		err = tl.RegisterSource(&testFetcher{name: "source1", interval: 10 * time.Second})
+		require.NoError(t, err)
+
+		tl.StartUpdate(ctx)
+		time.Sleep(150 * time.Millisecond)
+		tl.StopUpdate()
+		assert.Equal(t, 0, storage.deleted, "empty feed isn't applied by default")
+	})
+
+	t.Run("Empty feed allowed", func(t *testing.T) {
+		t.Parallel()
+		storage := &testStorage{trackers: []models.Tracker{testTracker1}}
+
+		tl, err := newTrackerListWithStorage(t, storage)
+		require.NoError(t, err)
+
+		err = tl.RegisterSource(&testEmptyFeedFetcher{testFetcher{name: "source1", interval: 10 * time.Second}})
+		require.NoError(t, err)
+
+		tl.StartUpdate(ctx)
+		time.Sleep(150 * time.Millisecond)
+		tl.StopUpdate()
+		assert.Equal(t, 1, storage.deleted, "the silent tracker is deleted")
+	})
+
+	t.Run("Failed fetch", func(t *testing.T) {
+		t.Parallel()
+		storage := &testStorage{trackers: []models.Tracker{testTracker1}}
+
+		tl, err := newTrackerListWithStorage(t, storage)
+		require.NoError(t, err)
+
+		err = tl.RegisterSource(&testFetcher{
+			err:      errors.New("connection reset"),
+			name:     "source1",
+			interval: 20 * time.Millisecond,
+		})
+		require.NoError(t, err)
+
+		tl.StartUpdate(ctx)
+		time.Sleep(150 * time.Millisecond)
+		tl.StopUpdate()
+		assert.Equal(t, 0, storage.deleted, "trackers are kept until a successful fetch")
+		assert.Equal(t, 0, storage.inserted, "no insertions expected")
+	})
+
+}
+
+func TestTrackerList_UpdateMeasurements(t *testing.T) {
+	testTracker := models.Tracker{
+		OrigId:      "1",
+		Source:      "source1",
+		Description: "1",
+		Latitude:    1,
+		Longitude:   1,
+	}
+
+	measurements := []models.Measurement{
+		{
+			TrackerId:  testTracker.Id(),
+			Pollutant:  models.PM25,
+			Value:      15,
+			Unit:       models.UnitMicrogramsPerCubicMeter,
+			ObservedAt: time.Date(2024, 3, 8, 10, 37, 33, 0, time.UTC),
+		},
+	}
+
+	ctx := context.Background()
+
+	t.Run("Measurements stored", func(t *testing.T) {
+		t.Parallel()
+		storage := &testStorage{}
+
+		tl, err := newTrackerListWithStorage(t, storage)
+		require.NoError(t, err)
+
+		err = tl.RegisterSource(&testMeasurementFetcher{
+			testFetcher: testFetcher{
+				data:     []models.Tracker{testTracker},
+				name:     "source1",
+				interval: 10 * time.Second,
+			},
+			measurements: measurements,
+		})
+		require.NoError(t, err)
+
+		tl.StartUpdate(ctx)
+		time.Sleep(150 * time.Millisecond)
+		tl.StopUpdate()
+		assert.Equal(t, measurements, storage.measurements)
+	})
+
+	t.Run("Partial result stored", func(t *testing.T) {
+		t.Parallel()
+		storage := &testStorage{}
+
+		tl, err := newTrackerListWithStorage(t, storage)
+		require.NoError(t, err)
+
+		err = tl.RegisterSource(&testMeasurementFetcher{
+			testFetcher: testFetcher{
+				data:     []models.Tracker{testTracker},
+				name:     "source1",
+				interval: 10 * time.Second,
+			},
+			measurements: measurements,
+			err:          errors.New("station 2: unexpected status"),
+		})
+		require.NoError(t, err)
+
+		tl.StartUpdate(ctx)
+		time.Sleep(150 * time.Millisecond)
+		tl.StopUpdate()
+		assert.Equal(t, measurements, storage.measurements)
+	})
+
+	t.Run("Wrapped fetcher", func(t *testing.T) {
+		t.Parallel()
+		storage := &testStorage{}
+
+		tl, err := newTrackerListWithStorage(t, storage)
+		require.NoError(t, err)
+
+		err = tl.RegisterSource(&testWrappingFetcher{fetcher: &testMeasurementFetcher{
+			testFetcher: testFetcher{
+				data:     []models.Tracker{testTracker},
+				name:     "source1",
+				interval: 10 * time.Second,
+			},
+			measurements: measurements,
+		}})
+		require.NoError(t, err)
+
+		tl.StartUpdate(ctx)
+		time.Sleep(150 * time.Millisecond)
+		tl.StopUpdate()
+		assert.Equal(t, measurements, storage.measurements)
+	})
 }
 
 func newTrackerListWithStorage(t *testing.T, storage trackerlist.Storage) (*trackerlist.TrackerList, error) {
@@ -285,3 +563,185 @@ func newTrackerListWithStorage(t *testing.T, storage trackerlist.Storage) (*trac
 	return trackerlist.New(slogdiscard.NewDiscardLogger(), otel.Tracer("test"), otel.Meter("test"), storage)
 
 }
+
+func TestTrackerList_Watch(t *testing.T) {
+	testTracker1 := models.Tracker{
+		OrigId:      "1",
+		Source:      "source1",
+		Description: "1",
+		Latitude:    1,
+		Longitude:   1,
+	}
+
+	testTracker2 := models.Tracker{
+		OrigId:      "2",
+		Source:      "source2",
+		Description: "2",
+		Latitude:    2,
+		Longitude:   2,
+	}
+
+	newFetchers := func() []*testFetcher {
+		return []*testFetcher{
+			{
+				data:     []models.Tracker{testTracker1},
+				name:     "source1",
+				interval: 10 * time.Second,
+			},
+			{
+				data:     []models.Tracker{testTracker2},
+				name:     "source2",
+				interval: 10 * time.Second,
+			},
+		}
+	}
+
+	receive := func(t *testing.T, events <-chan models.Event) (models.Event, bool) {
+		t.Helper()
+		select {
+		case ev, ok := <-events:
+			return ev, ok
+		case <-time.After(time.Second):
+			t.Fatal("no event received")
+		}
+		return models.Event{}, false
+	}
+
+	ctx := context.Background()
+
+	t.Run("Live events of a source", func(t *testing.T) {
+		t.Parallel()
+		storage := &testStorage{}
+
+		tl, err := newTrackerListWithStorage(t, storage)
+		require.NoError(t, err)
+
+		for _, f := range newFetchers() {
+			require.NoError(t, tl.RegisterSource(f))
+		}
+
+		events, err := tl.Watch(ctx, "source2", 0)
+		require.NoError(t, err)
+
+		tl.StartUpdate(ctx)
+
+		ev, ok := receive(t, events)
+		require.True(t, ok)
+		assert.Equal(t, models.ChangeInserted, ev.Type)
+		assert.Equal(t, testTracker2.Id(), ev.Tracker.Id())
+		assert.Positive(t, ev.Tracker.Revision)
+
+		tl.StopUpdate()
+
+		_, ok = receive(t, events)
+		require.False(t, ok, "channel is closed when update is stopped")
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		t.Parallel()
+		deleted := testTracker2
+		deleted.Revision = 6
+		deleted.DeletedAt = time.Now()
+
+		storage := &testStorage{trackers: []models.Tracker{deleted}}
+
+		tl, err := newTrackerListWithStorage(t, storage)
+		require.NoError(t, err)
+
+		wctx, cancel := context.WithCancel(ctx)
+		events, err := tl.Watch(wctx, "", 5)
+		require.NoError(t, err)
+
+		ev, ok := receive(t, events)
+		require.True(t, ok)
+		assert.Equal(t, models.ChangeDeleted, ev.Type)
+		assert.Equal(t, deleted, ev.Tracker)
+
+		cancel()
+		_, ok = receive(t, events)
+		require.False(t, ok, "channel is closed when context is done")
+	})
+
+	t.Run("Concurrent refreshes", func(t *testing.T) {
+		t.Parallel()
+		storage := &holdingStorage{hold: "source1", held: make(chan struct{}), release: make(chan struct{})}
+
+		tl, err := newTrackerListWithStorage(t, storage)
+		require.NoError(t, err)
+
+		fetchers := newFetchers()
+		require.NoError(t, tl.RegisterSource(fetchers[0]))
+		// source2 is refreshed while source1 is being applied
+		require.NoError(t, tl.RegisterSource(&waitingFetcher{testFetcher: fetchers[1], wait: storage.held}))
+
+		events, err := tl.Watch(ctx, "", 0)
+		require.NoError(t, err)
+
+		tl.StartUpdate(ctx)
+		defer tl.StopUpdate()
+
+		<-storage.held
+		select {
+		case ev := <-events:
+			t.Fatalf("revision %d is published before the held revision 1", ev.Tracker.Revision)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		close(storage.release)
+
+		ev, ok := receive(t, events)
+		require.True(t, ok)
+		assert.Equal(t, testTracker1.Id(), ev.Tracker.Id())
+		assert.Equal(t, models.Revision(1), ev.Tracker.Revision)
+
+		ev, ok = receive(t, events)
+		require.True(t, ok)
+		assert.Equal(t, testTracker2.Id(), ev.Tracker.Id())
+		assert.Equal(t, models.Revision(2), ev.Tracker.Revision)
+	})
+
+	t.Run("Unknown source", func(t *testing.T) {
+		t.Parallel()
+		tl, err := newTrackerListWithStorage(t, &testStorage{})
+		require.NoError(t, err)
+
+		_, err = tl.Watch(ctx, "unknown", 0)
+		require.ErrorIs(t, err, errStorage.ErrSourceNotFound)
+	})
+}
+
+// Holds Apply of the held source after it takes its revisions until release is closed
+type holdingStorage struct {
+	testStorage
+	mu      sync.Mutex
+	hold    models.SourceName
+	held    chan struct{}
+	release chan struct{}
+}
+
+func (hs *holdingStorage) Apply(ctx context.Context, changes models.Changeset) ([]models.Event, error) {
+	hs.mu.Lock()
+	events, err := hs.testStorage.Apply(ctx, changes)
+	hs.mu.Unlock()
+
+	if len(changes.Inserts) != 0 && changes.Inserts[0].SourceName() == hs.hold {
+		close(hs.held)
+		<-hs.release
+	}
+	return events, err
+}
+
+// Returns the feed once wait is closed
+type waitingFetcher struct {
+	*testFetcher
+	wait <-chan struct{}
+}
+
+func (wf *waitingFetcher) Fetch(ctx context.Context) ([]models.Tracker, error) {
+	select {
+	case <-wf.wait:
+		return wf.testFetcher.Fetch(ctx)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,161 @@
+package trackerlist
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Number of events kept for a subscriber which hasn't received them yet.
+// Subscribers falling behind further are dropped
+const watchBufferSize = 256
+
+type subscriber struct {
+	source models.SourceName
+	events chan models.Event
+}
+
+// Subscribes to tracker changes of the source or of all sources if the source is empty.
+// If after is positive, changes stored after the revision are replayed first;
+// the replayed insertions are reported as updates.
+//
+// The returned channel is closed when ctx is done, when the subscriber falls behind
+// or when the update is stopped. Events come in revision order,
+// the subscription can be resumed from the revision of the last received event
+func (tl *TrackerList) Watch(ctx context.Context, source models.SourceName, after models.Revision) (<-chan models.Event, error) {
+	const op = "TrackerList.Watch"
+	ctx, span := tl.tracer.Start(ctx, op)
+	defer span.End()
+
+	if len(source) != 0 {
+		if _, exists := tl.sources[source]; !exists {
+			span.SetStatus(codes.Error, "source not found")
+			return nil, fmt.Errorf("%s: %s: %w", op, source, storage.ErrSourceNotFound)
+		}
+	}
+
+	// subscribing before reading the storage guarantees nothing is lost between the replay and live events
+	sub := &subscriber{
+		source: source,
+		events: make(chan models.Event, watchBufferSize),
+	}
+	tl.subscribe(sub)
+
+	var replay []models.Tracker
+	if after > 0 {
+		list, err := tl.storage.ChangedTrackers(ctx, after)
+		if err != nil {
+			tl.unsubscribe(sub)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		for _, tr := range list {
+			if len(source) == 0 || tr.SourceName() == source {
+				replay = append(replay, tr)
+			}
+		}
+	}
+	span.SetAttributes(attribute.Int("events replayed", len(replay)))
+
+	out := make(chan models.Event)
+
+	go func() {
+		defer close(out)
+		defer tl.unsubscribe(sub)
+
+		// live events are deduplicated against the replay only
+		replayed := after
+
+		send := func(ev models.Event) bool {
+			select {
+			case out <- ev:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for _, tr := range replay {
+			ev := models.Event{Type: models.ChangeUpdated, Tracker: tr}
+			if tr.IsDeleted() {
+				ev.Type = models.ChangeDeleted
+			}
+			if !send(ev) {
+				return
+			}
+			replayed = max(replayed, tr.Revision)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-sub.events:
+				if !ok {
+					return
+				}
+				// the event has already been replayed
+				if ev.Tracker.Revision <= replayed {
+					continue
+				}
+				if !send(ev) {
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (tl *TrackerList) subscribe(sub *subscriber) {
+	tl.subMu.Lock()
+	defer tl.subMu.Unlock()
+	tl.subscribers[sub] = struct{}{}
+}
+
+func (tl *TrackerList) unsubscribe(sub *subscriber) {
+	tl.subMu.Lock()
+	defer tl.subMu.Unlock()
+	if _, exists := tl.subscribers[sub]; exists {
+		delete(tl.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Sends the event to the subscribers of its source.
+// Drops the subscribers whose buffers are full
+func (tl *TrackerList) publish(ev models.Event) {
+	const op = "TrackerList.publish"
+
+	tl.subMu.Lock()
+	defer tl.subMu.Unlock()
+
+	for sub := range tl.subscribers {
+		if len(sub.source) != 0 && sub.source != ev.Tracker.SourceName() {
+			continue
+		}
+		select {
+		case sub.events <- ev:
+		default:
+			tl.log.With(slog.String("op", op)).Warn("watch subscriber is too slow, dropping it")
+			delete(tl.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Closes all subscriptions
+func (tl *TrackerList) closeSubscribers() {
+	tl.subMu.Lock()
+	defer tl.subMu.Unlock()
+
+	for sub := range tl.subscribers {
+		delete(tl.subscribers, sub)
+		close(sub.events)
+	}
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+)
+
+// Backend specific writes of ApplyChangeset, each one returning the written tracker row.
+// Insert takes id, orig_id, source, description, latitude and longitude and returns no row for an existing tracker,
+// Update takes description, latitude, longitude and id, Delete takes id
+type ChangesetStatements struct {
+	Insert string
+	Update string
+	Delete string
+	Scan   func(row interface{ Scan(dest ...any) error }) (models.Tracker, error)
+}
+
+// Applies the changes within tx and returns them as events in the order they are written.
+// Updates and deletes of missing trackers are skipped, the transaction is left to the caller
+func ApplyChangeset(ctx context.Context, tx *sql.Tx, stmts ChangesetStatements, changes models.Changeset) ([]models.Event, error) {
+	events := make([]models.Event, 0, changes.Len())
+
+	if len(changes.Inserts) != 0 {
+		stmt, err := tx.PrepareContext(ctx, stmts.Insert)
+		if err != nil {
+			return nil, err
+		}
+		defer stmt.Close()
+
+		for _, tr := range changes.Inserts {
+			res, err := stmts.Scan(stmt.QueryRowContext(ctx,
+				tr.Id(), tr.OrigId, tr.Source, tr.Description, tr.Latitude, tr.Longitude))
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%s: %w", tr.Id(), ErrTrackerExists)
+			}
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, models.Event{Type: models.ChangeInserted, Tracker: res})
+		}
+	}
+
+	if len(changes.Updates) != 0 {
+		stmt, err := tx.PrepareContext(ctx, stmts.Update)
+		if err != nil {
+			return nil, err
+		}
+		defer stmt.Close()
+
+		for _, tr := range changes.Updates {
+			res, err := stmts.Scan(stmt.QueryRowContext(ctx,
+				tr.Description, tr.Latitude, tr.Longitude, tr.Id()))
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, models.Event{Type: models.ChangeUpdated, Tracker: res})
+		}
+	}
+
+	if len(changes.Deletes) != 0 {
+		stmt, err := tx.PrepareContext(ctx, stmts.Delete)
+		if err != nil {
+			return nil, err
+		}
+		defer stmt.Close()
+
+		for _, id := range changes.Deletes {
+			res, err := stmts.Scan(stmt.QueryRowContext(ctx, id))
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, models.Event{Type: models.ChangeDeleted, Tracker: res})
+		}
+	}
+
+	return events, nil
+}
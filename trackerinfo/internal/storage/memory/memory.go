@@ -0,0 +1,519 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/geo"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Keeps everything in maps guarded by a single lock.
+	// Nothing survives a restart, so it suits tests and throwaway instances
+	Storage struct {
+		mu           sync.RWMutex
+		tracer       trace.Tracer
+		now          func() time.Time
+		revision     models.Revision
+		trackers     map[models.Id]*record
+		history      map[models.Id][]models.TrackerVersion
+		measurements map[measurementKey]models.Measurement
+	}
+	Option func(*Storage)
+
+	record struct {
+		tracker    models.Tracker
+		modifiedAt time.Time
+	}
+	// Measurements are unique by tracker, pollutant and observation second as in sql storages
+	measurementKey struct {
+		trackerId  models.Id
+		pollutant  models.Pollutant
+		observedAt int64
+	}
+)
+
+// Sets the clock used for change timestamps
+func WithClock(now func() time.Time) Option {
+	return func(s *Storage) {
+		s.now = now
+	}
+}
+
+func New(tracer trace.Tracer, options ...Option) *Storage {
+	storage := &Storage{
+		tracer:       tracer,
+		now:          time.Now,
+		trackers:     make(map[models.Id]*record),
+		history:      make(map[models.Id][]models.TrackerVersion),
+		measurements: make(map[measurementKey]models.Measurement),
+	}
+
+	for _, opt := range options {
+		opt(storage)
+	}
+
+	return storage
+}
+
+// Inserts the tracker and returns the revision assigned to the change
+func (s *Storage) Insert(ctx context.Context, tracker models.Tracker) (models.Revision, error) {
+	const op = "memory.Insert"
+
+	_, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("trackerId", string(tracker.Id()))),
+	)
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// a tracker which has been deleted before is brought back from its tombstone
+	if rec, ok := s.trackers[tracker.Id()]; ok && !rec.tracker.IsDeleted() {
+		span.SetStatus(codes.Error, storage.ErrTrackerExists.Error())
+		return 0, storage.ErrTrackerExists
+	}
+
+	tracker.DeletedAt = time.Time{}
+
+	return s.write(tracker), nil
+}
+
+// Updates the tracker and returns the revision assigned to the change.
+// Returns zero revision if there is no such tracker
+func (s *Storage) Update(ctx context.Context, tracker models.Tracker) (models.Revision, error) {
+	const op = "memory.Update"
+
+	_, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("trackerId", string(tracker.Id()))),
+	)
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.trackers[tracker.Id()]
+	if !ok || rec.tracker.IsDeleted() {
+		return 0, nil
+	}
+
+	upd := rec.tracker
+	upd.Description = tracker.Description
+	upd.Latitude = tracker.Latitude
+	upd.Longitude = tracker.Longitude
+
+	return s.write(upd), nil
+}
+
+// Marks the tracker as deleted and returns the revision assigned to the change.
+// Returns zero revision if there is no such tracker
+func (s *Storage) Delete(ctx context.Context, id models.Id) (models.Revision, error) {
+	const op = "memory.Delete"
+
+	_, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("trackerId", string(id))),
+	)
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.trackers[id]
+	if !ok || rec.tracker.IsDeleted() {
+		return 0, nil
+	}
+
+	deleted := rec.tracker
+	deleted.DeletedAt = s.now().UTC()
+
+	return s.write(deleted), nil
+}
+
+// Applies the changes at once and returns them as events ordered by revision.
+// Updates and deletes of missing trackers are skipped. Nothing is applied if an insert conflicts
+func (s *Storage) Apply(ctx context.Context, changes models.Changeset) ([]models.Event, error) {
+	const op = "memory.Apply"
+
+	_, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(
+			attribute.Int("inserts", len(changes.Inserts)),
+			attribute.Int("updates", len(changes.Updates)),
+			attribute.Int("deletes", len(changes.Deletes)),
+		),
+	)
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inserted := make(map[models.Id]struct{}, len(changes.Inserts))
+	for _, tr := range changes.Inserts {
+		_, dup := inserted[tr.Id()]
+		if rec, ok := s.trackers[tr.Id()]; dup || ok && !rec.tracker.IsDeleted() {
+			span.SetStatus(codes.Error, storage.ErrTrackerExists.Error())
+			return nil, fmt.Errorf("%s: %w", tr.Id(), storage.ErrTrackerExists)
+		}
+		inserted[tr.Id()] = struct{}{}
+	}
+
+	events := make([]models.Event, 0, changes.Len())
+
+	for _, tr := range changes.Inserts {
+		tr.DeletedAt = time.Time{}
+		tr.Revision = s.write(tr)
+		events = append(events, models.Event{Type: models.ChangeInserted, Tracker: tr})
+	}
+
+	for _, tr := range changes.Updates {
+		rec, ok := s.trackers[tr.Id()]
+		if !ok || rec.tracker.IsDeleted() {
+			continue
+		}
+		upd := rec.tracker
+		upd.Description = tr.Description
+		upd.Latitude = tr.Latitude
+		upd.Longitude = tr.Longitude
+		upd.Revision = s.write(upd)
+		events = append(events, models.Event{Type: models.ChangeUpdated, Tracker: upd})
+	}
+
+	for _, id := range changes.Deletes {
+		rec, ok := s.trackers[id]
+		if !ok || rec.tracker.IsDeleted() {
+			continue
+		}
+		deleted := rec.tracker
+		deleted.DeletedAt = s.now().UTC()
+		deleted.Revision = s.write(deleted)
+		events = append(events, models.Event{Type: models.ChangeDeleted, Tracker: deleted})
+	}
+
+	span.SetAttributes(attribute.Int("changes applied", len(events)))
+
+	return events, nil
+}
+
+func (s *Storage) Trackers(ctx context.Context) ([]models.Tracker, error) {
+	const op = "memory.Trackers"
+	_, span := s.tracer.Start(ctx, op)
+	defer span.End()
+
+	res := s.filter(func(rec *record) bool {
+		return !rec.tracker.IsDeleted()
+	})
+
+	span.SetAttributes(attribute.Int("trackers returned", len(res)))
+
+	return res, nil
+}
+
+// Returns trackers modified since modifiedFrom including the deleted ones
+func (s *Storage) ModifiedTrackers(ctx context.Context, modifiedFrom time.Time) ([]models.Tracker, error) {
+	const op = "memory.ModifiedTrackers"
+	_, span := s.tracer.Start(ctx, op)
+	defer span.End()
+
+	if modifiedFrom.IsZero() {
+		span.SetStatus(codes.Error, "modifiedFrom argument is zero")
+		return nil, errors.New("modifiedFrom argument is zero")
+	}
+
+	res := s.filter(func(rec *record) bool {
+		return !rec.modifiedAt.Before(modifiedFrom)
+	})
+
+	span.SetAttributes(attribute.Int("trackers returned", len(res)))
+
+	return res, nil
+}
+
+// Returns trackers changed after the revision including the deleted ones, ordered by revision
+func (s *Storage) ChangedTrackers(ctx context.Context, after models.Revision) ([]models.Tracker, error) {
+	const op = "memory.ChangedTrackers"
+	_, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.Int64("after", int64(after))),
+	)
+	defer span.End()
+
+	res := s.filter(func(rec *record) bool {
+		return rec.tracker.Revision > after
+	})
+
+	span.SetAttributes(attribute.Int("trackers returned", len(res)))
+
+	return res, nil
+}
+
+func (s *Storage) Sources(ctx context.Context) ([]string, error) {
+	const op = "memory.Sources"
+	_, span := s.tracer.Start(ctx, op)
+	defer span.End()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]struct{})
+	var res []string
+
+	for _, rec := range s.trackers {
+		if rec.tracker.IsDeleted() {
+			continue
+		}
+		if _, ok := seen[rec.tracker.Source]; ok {
+			continue
+		}
+		seen[rec.tracker.Source] = struct{}{}
+		res = append(res, rec.tracker.Source)
+	}
+	sort.Strings(res)
+
+	span.SetAttributes(attribute.Int("Sources returned", len(res)))
+	return res, nil
+}
+
+func (s *Storage) IdsBySource(ctx context.Context, source string) ([]string, error) {
+	const op = "memory.IdsBySource"
+	_, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("source", source)),
+	)
+	defer span.End()
+
+	trackers := s.filter(func(rec *record) bool {
+		return rec.tracker.Source == source && !rec.tracker.IsDeleted()
+	})
+
+	if len(trackers) == 0 {
+		span.SetStatus(codes.Error, storage.ErrSourceNotFound.Error())
+		return nil, storage.ErrSourceNotFound
+	}
+
+	res := make([]string, 0, len(trackers))
+	for _, tr := range trackers {
+		res = append(res, tr.OrigId)
+	}
+
+	span.SetAttributes(attribute.Int("Ids returned", len(res)))
+	return res, nil
+}
+
+// Stores measurements. Measurements that have already been stored are skipped
+func (s *Storage) AddMeasurements(ctx context.Context, measurements []models.Measurement) error {
+	const op = "memory.AddMeasurements"
+	_, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.Int("measurements", len(measurements))),
+	)
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, m := range measurements {
+		key := measurementKey{
+			trackerId:  m.TrackerId,
+			pollutant:  m.Pollutant,
+			observedAt: m.ObservedAt.Unix(),
+		}
+		if _, ok := s.measurements[key]; ok {
+			continue
+		}
+		// the same precision as sql storages keep
+		m.ObservedAt = time.Unix(key.observedAt, 0).UTC()
+		s.measurements[key] = m
+	}
+
+	return nil
+}
+
+// Returns every version of the tracker ordered by revision
+func (s *Storage) History(ctx context.Context, id models.Id) ([]models.TrackerVersion, error) {
+	const op = "memory.History"
+	_, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("trackerId", string(id))),
+	)
+	defer span.End()
+
+	s.mu.RLock()
+	res := append([]models.TrackerVersion(nil), s.history[id]...)
+	s.mu.RUnlock()
+
+	span.SetAttributes(attribute.Int("versions returned", len(res)))
+
+	return res, nil
+}
+
+// Returns measurements of the tracker observed within [from, to) ordered by observation time
+func (s *Storage) Measurements(ctx context.Context, id models.Id, from, to time.Time) ([]models.Measurement, error) {
+	const op = "memory.Measurements"
+	_, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("trackerId", string(id))),
+	)
+	defer span.End()
+
+	if !from.Before(to) {
+		span.SetStatus(codes.Error, "time range is empty")
+		return nil, errors.New("time range is empty")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var res []models.Measurement
+
+	for key, m := range s.measurements {
+		if key.trackerId == id && key.observedAt >= from.Unix() && key.observedAt < to.Unix() {
+			res = append(res, m)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].ObservedAt.Equal(res[j].ObservedAt) {
+			return res[i].ObservedAt.Before(res[j].ObservedAt)
+		}
+		return res[i].Pollutant < res[j].Pollutant
+	})
+
+	span.SetAttributes(attribute.Int("measurements returned", len(res)))
+
+	return res, nil
+}
+
+// Returns up to limit trackers within radius meters of the point ordered by distance
+func (s *Storage) Nearest(ctx context.Context, point geo.Point, radius float64, limit int) ([]models.NearTracker, error) {
+	const op = "memory.Nearest"
+	_, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(
+			attribute.Float64("latitude", point.Latitude),
+			attribute.Float64("longitude", point.Longitude),
+			attribute.Float64("radius", radius),
+		),
+	)
+	defer span.End()
+
+	candidates := s.filter(func(rec *record) bool {
+		return !rec.tracker.IsDeleted()
+	})
+
+	var res []models.NearTracker
+
+	for _, tr := range candidates {
+		distance := geo.Distance(point, geo.Point{Latitude: tr.Latitude, Longitude: tr.Longitude})
+		if distance <= radius {
+			res = append(res, models.NearTracker{Tracker: tr, Distance: distance})
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Distance < res[j].Distance
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	span.SetAttributes(attribute.Int("trackers returned", len(res)))
+
+	return res, nil
+}
+
+// Returns trackers inside the rectangle, of the given sources only if there are any
+func (s *Storage) TrackersInBounds(ctx context.Context, bounds geo.Bounds, sources []string) ([]models.Tracker, error) {
+	const op = "memory.TrackersInBounds"
+	_, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(
+			attribute.Float64("south", bounds.South),
+			attribute.Float64("west", bounds.West),
+			attribute.Float64("north", bounds.North),
+			attribute.Float64("east", bounds.East),
+			attribute.StringSlice("sources", sources),
+		),
+	)
+	defer span.End()
+
+	wanted := make(map[string]struct{}, len(sources))
+	for _, source := range sources {
+		wanted[source] = struct{}{}
+	}
+
+	res := s.filter(func(rec *record) bool {
+		if rec.tracker.IsDeleted() {
+			return false
+		}
+		if _, ok := wanted[rec.tracker.Source]; len(wanted) != 0 && !ok {
+			return false
+		}
+		return bounds.Contains(geo.Point{Latitude: rec.tracker.Latitude, Longitude: rec.tracker.Longitude})
+	})
+
+	span.SetAttributes(attribute.Int("trackers returned", len(res)))
+
+	return res, nil
+}
+
+// Stores the tracker under the next revision and records its version.
+// The caller holds the write lock
+func (s *Storage) write(tracker models.Tracker) models.Revision {
+	s.revision++
+	tracker.Revision = s.revision
+	now := s.now()
+
+	id := tracker.Id()
+	kind := models.VersionCreated
+
+	if prev, ok := s.trackers[id]; ok {
+		switch {
+		case tracker.IsDeleted():
+			kind = models.VersionRemoved
+			now = tracker.DeletedAt
+		case prev.tracker.IsDeleted():
+			kind = models.VersionCreated
+		case prev.tracker.Latitude != tracker.Latitude || prev.tracker.Longitude != tracker.Longitude:
+			kind = models.VersionMoved
+		default:
+			kind = models.VersionRenamed
+		}
+
+		versions := s.history[id]
+		versions[len(versions)-1].ValidTo = now.UTC()
+	}
+
+	s.history[id] = append(s.history[id], models.TrackerVersion{
+		Tracker:   tracker,
+		Kind:      kind,
+		ValidFrom: now.UTC(),
+	})
+
+	s.trackers[id] = &record{
+		tracker:    tracker,
+		modifiedAt: now,
+	}
+
+	return tracker.Revision
+}
+
+// Returns copies of the matching trackers ordered by revision
+func (s *Storage) filter(match func(rec *record) bool) []models.Tracker {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var res []models.Tracker
+
+	for _, rec := range s.trackers {
+		if match(rec) {
+			res = append(res, rec.tracker)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Revision < res[j].Revision
+	})
+
+	return res
+}
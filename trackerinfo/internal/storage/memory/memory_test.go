@@ -0,0 +1,75 @@
+package memory_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage/memory"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestMemory(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		return memory.New(otel.Tracer(""))
+	})
+}
+
+func TestMemory_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.New(otel.Tracer(""))
+
+	const writers = 8
+	var wg sync.WaitGroup
+
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				tr := models.Tracker{OrigId: fmt.Sprint(i), Source: fmt.Sprint("source", w)}
+				_, err := storage.Insert(ctx, tr)
+				assert.NoError(t, err)
+				_, err = storage.Trackers(ctx)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	res, err := storage.ChangedTrackers(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, res, writers*50)
+	for i, tr := range res {
+		require.Equal(t, models.Revision(i+1), tr.Revision)
+	}
+}
+
+func TestMemory_Clock(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 8, 10, 0, 0, 0, time.UTC)
+	storage := memory.New(otel.Tracer(""), memory.WithClock(func() time.Time { return now }))
+
+	tr := models.Tracker{OrigId: "1", Source: "test"}
+	_, err := storage.Insert(ctx, tr)
+	require.NoError(t, err)
+
+	now = now.Add(time.Hour)
+	_, err = storage.Delete(ctx, tr.Id())
+	require.NoError(t, err)
+
+	res, err := storage.ModifiedTrackers(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, now, res[0].DeletedAt)
+
+	res, err = storage.ModifiedTrackers(ctx, now.Add(time.Second))
+	require.NoError(t, err)
+	require.Empty(t, res)
+}
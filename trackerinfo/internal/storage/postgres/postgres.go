@@ -0,0 +1,671 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/geo"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Key of the advisory lock held while a revision is taken and written.
+// Holding it makes revisions visible in the order they are taken from the sequence,
+// so a client syncing by revision can't skip a change committed later with a smaller number
+const revisionLock = 0x736d6f67
+
+// Columns read by scanTrackers
+const trackerColumns = `orig_id, source, description, latitude, longitude, deletedAt, revision`
+
+// Writes shared by the single change methods and Apply, completed with a RETURNING clause.
+// A tracker which has been deleted before is brought back from its tombstone by insertTracker
+const (
+	insertTracker = `INSERT INTO
+								trackers(id, orig_id, source, description, latitude, longitude, revision)
+								VALUES($1, $2, $3, $4, $5, $6, nextval('trackers_revision_seq'))
+								ON CONFLICT(id) DO UPDATE
+								SET description = excluded.description,
+									latitude = excluded.latitude,
+									longitude = excluded.longitude,
+									modifiedAt = now(),
+									deletedAt = NULL,
+									revision = excluded.revision
+								WHERE trackers.deletedAt IS NOT NULL`
+	updateTracker = `UPDATE trackers
+								SET description = $1, latitude = $2, longitude = $3,
+									modifiedAt = now(),
+									revision = nextval('trackers_revision_seq')
+								WHERE id = $4 AND deletedAt IS NULL`
+	deleteTracker = `UPDATE trackers
+								SET deletedAt = now(),
+									modifiedAt = now(),
+									revision = nextval('trackers_revision_seq')
+								WHERE id = $1 AND deletedAt IS NULL`
+)
+
+// Writes of Apply
+var applyStatements = storage.ChangesetStatements{
+	Insert: insertTracker + ` RETURNING ` + trackerColumns,
+	Update: updateTracker + ` RETURNING ` + trackerColumns,
+	Delete: deleteTracker + ` RETURNING ` + trackerColumns,
+	Scan:   scanTracker,
+}
+
+type (
+	Storage struct {
+		db     *sql.DB
+		tracer trace.Tracer
+	}
+	Option func(*Storage) error
+)
+
+// Connects to the database by a libpq connection string or URL
+func WithDSN(dsn string) Option {
+	return func(s *Storage) error {
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			return err
+		}
+		if err := db.Ping(); err != nil {
+			return err
+		}
+		s.db = db
+		return nil
+	}
+}
+
+func WithDatabaseInstance(db *sql.DB) Option {
+	return func(s *Storage) error {
+		if err := db.Ping(); err != nil {
+			return err
+		}
+		s.db = db
+		return nil
+	}
+}
+
+// One of the options setting the database is required
+func New(tracer trace.Tracer, options ...Option) (*Storage, error) {
+
+	storage := &Storage{tracer: tracer}
+
+	for _, opt := range options {
+		if err := opt(storage); err != nil {
+			return nil, err
+		}
+	}
+
+	if storage.db == nil {
+		return nil, errors.New("database is not set")
+	}
+
+	return storage, nil
+}
+
+// Inserts the tracker and returns the revision assigned to the change
+func (s *Storage) Insert(ctx context.Context, tracker models.Tracker) (models.Revision, error) {
+	const op = "postgres.Insert"
+
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("trackerId", string(tracker.Id()))),
+	)
+	defer span.End()
+
+	revision, err := s.writeRevision(ctx, insertTracker+` RETURNING revision`,
+		tracker.Id(),
+		tracker.OrigId,
+		tracker.Source,
+		tracker.Description,
+		tracker.Latitude,
+		tracker.Longitude)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			span.SetStatus(codes.Error, storage.ErrTrackerExists.Error())
+			return 0, storage.ErrTrackerExists
+		}
+		span.SetStatus(codes.Error, "db error")
+		return 0, err
+	}
+
+	return revision, nil
+}
+
+// Updates the tracker and returns the revision assigned to the change.
+// Returns zero revision if there is no such tracker
+func (s *Storage) Update(ctx context.Context, tracker models.Tracker) (models.Revision, error) {
+	const op = "postgres.Update"
+
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("trackerId", string(tracker.Id()))),
+	)
+	defer span.End()
+
+	revision, err := s.writeRevision(ctx, updateTracker+` RETURNING revision`,
+		tracker.Description,
+		tracker.Latitude,
+		tracker.Longitude,
+		tracker.Id())
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.SetStatus(codes.Error, "db error")
+		return 0, err
+	}
+
+	return revision, nil
+}
+
+// Marks the tracker as deleted and returns the revision assigned to the change.
+// The row is kept as a tombstone so incremental listings are able to report the removal.
+// Returns zero revision if there is no such tracker
+func (s *Storage) Delete(ctx context.Context, id models.Id) (models.Revision, error) {
+	const op = "postgres.Delete"
+
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("trackerId", string(id))),
+	)
+	defer span.End()
+
+	revision, err := s.writeRevision(ctx, deleteTracker+` RETURNING revision`,
+		id)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.SetStatus(codes.Error, "db error")
+		return 0, err
+	}
+
+	return revision, nil
+}
+
+// Applies the changes in a single transaction and returns them as events ordered by revision.
+// Updates and deletes of missing trackers are skipped. Nothing is applied if any change fails
+func (s *Storage) Apply(ctx context.Context, changes models.Changeset) ([]models.Event, error) {
+	const op = "postgres.Apply"
+
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(
+			attribute.Int("inserts", len(changes.Inserts)),
+			attribute.Int("updates", len(changes.Updates)),
+			attribute.Int("deletes", len(changes.Deletes)),
+		),
+	)
+	defer span.End()
+
+	tx, err := s.beginWrite(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	events, err := storage.ApplyChangeset(ctx, tx, applyStatements, changes)
+	if err != nil {
+		if errors.Is(err, storage.ErrTrackerExists) {
+			span.SetStatus(codes.Error, storage.ErrTrackerExists.Error())
+		} else {
+			span.SetStatus(codes.Error, "db error")
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("changes applied", len(events)))
+
+	return events, nil
+}
+
+func (s *Storage) Trackers(ctx context.Context) ([]models.Tracker, error) {
+	const op = "postgres.Trackers"
+	ctx, span := s.tracer.Start(ctx, op)
+	defer span.End()
+
+	res, err := s.queryTrackers(ctx, `SELECT `+trackerColumns+`
+								FROM trackers
+								WHERE deletedAt IS NULL
+								ORDER BY revision`)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("trackers returned", len(res)))
+
+	return res, nil
+}
+
+// Returns trackers modified since modifiedFrom including the deleted ones
+func (s *Storage) ModifiedTrackers(ctx context.Context, modifiedFrom time.Time) ([]models.Tracker, error) {
+	const op = "postgres.ModifiedTrackers"
+	ctx, span := s.tracer.Start(ctx, op)
+	defer span.End()
+
+	if modifiedFrom.IsZero() {
+		span.SetStatus(codes.Error, "modifiedFrom argument is zero")
+		return nil, errors.New("modifiedFrom argument is zero")
+	}
+
+	// second resolution as in the sqlite storage
+	res, err := s.queryTrackers(ctx, `SELECT `+trackerColumns+`
+								FROM trackers
+								WHERE modifiedAt >= to_timestamp($1)
+								ORDER BY revision`,
+		modifiedFrom.Unix())
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("trackers returned", len(res)))
+
+	return res, nil
+}
+
+// Returns trackers changed after the revision including the deleted ones, ordered by revision
+func (s *Storage) ChangedTrackers(ctx context.Context, after models.Revision) ([]models.Tracker, error) {
+	const op = "postgres.ChangedTrackers"
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.Int64("after", int64(after))),
+	)
+	defer span.End()
+
+	res, err := s.queryTrackers(ctx, `SELECT `+trackerColumns+`
+								FROM trackers
+								WHERE revision > $1
+								ORDER BY revision`,
+		after)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("trackers returned", len(res)))
+
+	return res, nil
+}
+
+func (s *Storage) Sources(ctx context.Context) ([]string, error) {
+	const op = "postgres.Sources"
+	ctx, span := s.tracer.Start(ctx, op)
+	defer span.End()
+
+	res, err := s.queryStrings(ctx, `SELECT DISTINCT source
+								FROM trackers
+								WHERE deletedAt IS NULL
+								ORDER BY source`)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("Sources returned", len(res)))
+	return res, nil
+}
+
+func (s *Storage) IdsBySource(ctx context.Context, source string) ([]string, error) {
+	const op = "postgres.IdsBySource"
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("source", source)),
+	)
+	defer span.End()
+
+	res, err := s.queryStrings(ctx, `SELECT orig_id
+								FROM trackers
+								WHERE source = $1 AND deletedAt IS NULL
+								ORDER BY revision`,
+		source)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		span.SetStatus(codes.Error, storage.ErrSourceNotFound.Error())
+		return nil, storage.ErrSourceNotFound
+	}
+
+	span.SetAttributes(attribute.Int("Ids returned", len(res)))
+	return res, nil
+}
+
+// Stores measurements in a single transaction.
+// Measurements that have already been stored are skipped
+func (s *Storage) AddMeasurements(ctx context.Context, measurements []models.Measurement) error {
+	const op = "postgres.AddMeasurements"
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.Int("measurements", len(measurements))),
+	)
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO
+								measurements(tracker_id, pollutant, value, unit, observedAt)
+								VALUES($1, $2, $3, $4, $5)
+								ON CONFLICT DO NOTHING`)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return err
+	}
+	defer stmt.Close()
+
+	for _, m := range measurements {
+		_, err := stmt.ExecContext(ctx,
+			m.TrackerId,
+			m.Pollutant,
+			m.Value,
+			m.Unit,
+			m.ObservedAt.Unix())
+		if err != nil {
+			span.SetStatus(codes.Error, "db error")
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return err
+	}
+
+	return nil
+}
+
+// Returns every version of the tracker ordered by revision
+func (s *Storage) History(ctx context.Context, id models.Id) ([]models.TrackerVersion, error) {
+	const op = "postgres.History"
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("trackerId", string(id))),
+	)
+	defer span.End()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT orig_id, source, description, latitude, longitude,
+									revision, kind, validFrom, validTo
+								FROM tracker_history
+								WHERE tracker_id = $1
+								ORDER BY revision`,
+		id)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []models.TrackerVersion
+
+	for rows.Next() {
+		var (
+			v       models.TrackerVersion
+			validTo sql.NullTime
+		)
+		err := rows.Scan(&v.Tracker.OrigId, &v.Tracker.Source, &v.Tracker.Description,
+			&v.Tracker.Latitude, &v.Tracker.Longitude, &v.Tracker.Revision,
+			&v.Kind, &v.ValidFrom, &validTo)
+		if err != nil {
+			span.SetStatus(codes.Error, "db error")
+			return nil, err
+		}
+		v.ValidFrom = v.ValidFrom.UTC()
+		if validTo.Valid {
+			v.ValidTo = validTo.Time.UTC()
+		}
+		if v.Kind == models.VersionRemoved {
+			v.Tracker.DeletedAt = v.ValidFrom
+		}
+		res = append(res, v)
+	}
+	if err := rows.Err(); err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("versions returned", len(res)))
+
+	return res, nil
+}
+
+// Returns measurements of the tracker observed within [from, to) ordered by observation time
+func (s *Storage) Measurements(ctx context.Context, id models.Id, from, to time.Time) ([]models.Measurement, error) {
+	const op = "postgres.Measurements"
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("trackerId", string(id))),
+	)
+	defer span.End()
+
+	if !from.Before(to) {
+		span.SetStatus(codes.Error, "time range is empty")
+		return nil, errors.New("time range is empty")
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT tracker_id, pollutant, value, unit, observedAt
+								FROM measurements
+								WHERE tracker_id = $1 AND observedAt >= $2 AND observedAt < $3
+								ORDER BY observedAt, pollutant`,
+		id, from.Unix(), to.Unix())
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []models.Measurement
+
+	for rows.Next() {
+		var (
+			m          models.Measurement
+			observedAt int64
+		)
+		err := rows.Scan(&m.TrackerId, &m.Pollutant, &m.Value, &m.Unit, &observedAt)
+		if err != nil {
+			span.SetStatus(codes.Error, "db error")
+			return nil, err
+		}
+		m.ObservedAt = time.Unix(observedAt, 0).UTC()
+		res = append(res, m)
+	}
+	if err := rows.Err(); err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("measurements returned", len(res)))
+
+	return res, nil
+}
+
+// Returns up to limit trackers within radius meters of the point ordered by distance.
+// Candidates are selected by the bounding rectangle of the circle, then filtered by exact distance
+func (s *Storage) Nearest(ctx context.Context, point geo.Point, radius float64, limit int) ([]models.NearTracker, error) {
+	const op = "postgres.Nearest"
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(
+			attribute.Float64("latitude", point.Latitude),
+			attribute.Float64("longitude", point.Longitude),
+			attribute.Float64("radius", radius),
+		),
+	)
+	defer span.End()
+
+	candidates, err := s.trackersInBounds(ctx, geo.BoundsAround(point, radius), nil)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+
+	var res []models.NearTracker
+
+	for _, tr := range candidates {
+		distance := geo.Distance(point, geo.Point{Latitude: tr.Latitude, Longitude: tr.Longitude})
+		if distance <= radius {
+			res = append(res, models.NearTracker{Tracker: tr, Distance: distance})
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Distance < res[j].Distance
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	span.SetAttributes(attribute.Int("trackers returned", len(res)))
+
+	return res, nil
+}
+
+// Returns trackers inside the rectangle, of the given sources only if there are any
+func (s *Storage) TrackersInBounds(ctx context.Context, bounds geo.Bounds, sources []string) ([]models.Tracker, error) {
+	const op = "postgres.TrackersInBounds"
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(
+			attribute.Float64("south", bounds.South),
+			attribute.Float64("west", bounds.West),
+			attribute.Float64("north", bounds.North),
+			attribute.Float64("east", bounds.East),
+			attribute.StringSlice("sources", sources),
+		),
+	)
+	defer span.End()
+
+	res, err := s.trackersInBounds(ctx, bounds, sources)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("trackers returned", len(res)))
+
+	return res, nil
+}
+
+// Uses the partial trackers_location index
+func (s *Storage) trackersInBounds(ctx context.Context, bounds geo.Bounds, sources []string) ([]models.Tracker, error) {
+	lngCond := `longitude BETWEEN $3 AND $4`
+	if bounds.CrossesAntimeridian() {
+		lngCond = `(longitude >= $3 OR longitude <= $4)`
+	}
+
+	args := []any{bounds.South, bounds.North, bounds.West, bounds.East}
+
+	var sourceCond string
+	if len(sources) != 0 {
+		sourceCond = ` AND source = ANY($` + strconv.Itoa(len(args)+1) + `)`
+		args = append(args, pq.Array(sources))
+	}
+
+	return s.queryTrackers(ctx, `SELECT `+trackerColumns+`
+								FROM trackers
+								WHERE deletedAt IS NULL
+									AND latitude BETWEEN $1 AND $2
+									AND `+lngCond+sourceCond+`
+								ORDER BY revision`,
+		args...)
+}
+
+// Runs the returning write query in a transaction holding the revision lock
+func (s *Storage) writeRevision(ctx context.Context, query string, args ...any) (models.Revision, error) {
+	tx, err := s.beginWrite(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var revision models.Revision
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&revision); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return revision, nil
+}
+
+// Begins a transaction holding the revision lock
+func (s *Storage) beginWrite(ctx context.Context) (*sql.Tx, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, revisionLock); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (s *Storage) queryTrackers(ctx context.Context, query string, args ...any) ([]models.Tracker, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []models.Tracker
+
+	for rows.Next() {
+		tr, err := scanTracker(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, tr)
+	}
+
+	return res, rows.Err()
+}
+
+// Scans trackerColumns of a single row
+func scanTracker(row interface{ Scan(dest ...any) error }) (models.Tracker, error) {
+	var (
+		tr        models.Tracker
+		deletedAt sql.NullTime
+	)
+	err := row.Scan(&tr.OrigId, &tr.Source, &tr.Description, &tr.Latitude, &tr.Longitude,
+		&deletedAt, &tr.Revision)
+	if err != nil {
+		return models.Tracker{}, err
+	}
+	if deletedAt.Valid {
+		tr.DeletedAt = deletedAt.Time.UTC()
+	}
+
+	return tr, nil
+}
+
+func (s *Storage) queryStrings(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []string
+
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+	}
+
+	return res, rows.Err()
+}
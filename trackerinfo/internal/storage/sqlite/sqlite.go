@@ -4,16 +4,56 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/geo"
 	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
 	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage"
-	"github.com/mattn/go-sqlite3"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// SQL expression evaluating to the revision of the next change.
+// SQLite serialises writes, so the value is unique within a statement
+const nextRevision = `(SELECT COALESCE(MAX(revision), 0) + 1 FROM trackers)`
+
+// Columns read by scanTrackers
+const trackerColumns = `orig_id, source, description, latitude, longitude, deletedAt, revision`
+
+// Writes shared by the single change methods and Apply, completed with a RETURNING clause.
+// A tracker which has been deleted before is brought back from its tombstone by insertTracker
+const (
+	insertTracker = `INSERT INTO
+								trackers(id, orig_id, source, description, latitude, longitude, revision)
+								VALUES(?, ?, ?, ?, ?, ?, ` + nextRevision + `)
+								ON CONFLICT(id) DO UPDATE
+								SET description = excluded.description,
+									latitude = excluded.latitude,
+									longitude = excluded.longitude,
+									deletedAt = NULL,
+									revision = excluded.revision
+								WHERE deletedAt IS NOT NULL`
+	updateTracker = `UPDATE trackers
+								SET description = ?, latitude = ?, longitude = ?,
+									revision = ` + nextRevision + `
+								WHERE id = ? AND deletedAt IS NULL`
+	deleteTracker = `UPDATE trackers
+								SET deletedAt = CURRENT_TIMESTAMP,
+									revision = ` + nextRevision + `
+								WHERE id = ? AND deletedAt IS NULL`
+)
+
+// Writes of Apply
+var applyStatements = storage.ChangesetStatements{
+	Insert: insertTracker + ` RETURNING ` + trackerColumns,
+	Update: updateTracker + ` RETURNING ` + trackerColumns,
+	Delete: deleteTracker + ` RETURNING ` + trackerColumns,
+	Scan:   scanTracker,
+}
+
 type (
 	Storage struct {
 		db     *sql.DB
@@ -68,7 +108,8 @@ func New(tracer trace.Tracer, options ...Option) (*Storage, error) {
 	return storage, nil
 }
 
-func (s *Storage) Insert(ctx context.Context, tracker models.Tracker) error {
+// Inserts the tracker and returns the revision assigned to the change
+func (s *Storage) Insert(ctx context.Context, tracker models.Tracker) (models.Revision, error) {
 	const op = "sqlite.Insert"
 
 	ctx, span := s.tracer.Start(ctx, op,
@@ -76,36 +117,37 @@ func (s *Storage) Insert(ctx context.Context, tracker models.Tracker) error {
 	)
 	defer span.End()
 
-	stmt, err := s.db.Prepare(`INSERT INTO 
-								trackers(id, orig_id, source, description, latitude, longitude)
-								VALUES(?, ?, ?, ?, ?, ?)`)
+	stmt, err := s.db.Prepare(insertTracker + ` RETURNING revision`)
 	if err != nil {
 		span.SetStatus(codes.Error, "db error")
-		return err
+		return 0, err
 	}
-	_, err = stmt.ExecContext(ctx,
+
+	var revision models.Revision
+	err = stmt.QueryRowContext(ctx,
 		tracker.Id(),
 		tracker.OrigId,
 		tracker.Source,
 		tracker.Description,
 		tracker.Latitude,
-		tracker.Longitude)
+		tracker.Longitude).Scan(&revision)
 
 	if err != nil {
-		var sqliteErr sqlite3.Error
-		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+		if errors.Is(err, sql.ErrNoRows) {
 			span.SetStatus(codes.Error, storage.ErrTrackerExists.Error())
-			return storage.ErrTrackerExists
+			return 0, storage.ErrTrackerExists
 		}
 		span.SetStatus(codes.Error, "db error")
-		return err
+		return 0, err
 	}
 
-	return nil
+	return revision, nil
 
 }
 
-func (s *Storage) Update(ctx context.Context, tracker models.Tracker) error {
+// Updates the tracker and returns the revision assigned to the change.
+// Returns zero revision if there is no such tracker
+func (s *Storage) Update(ctx context.Context, tracker models.Tracker) (models.Revision, error) {
 	const op = "sqlite.Update"
 
 	ctx, span := s.tracer.Start(ctx, op,
@@ -113,28 +155,31 @@ func (s *Storage) Update(ctx context.Context, tracker models.Tracker) error {
 	)
 	defer span.End()
 
-	stmt, err := s.db.Prepare(`UPDATE trackers
-								SET description = ?, latitude = ?, longitude = ?
-								WHERE id = ?`)
+	stmt, err := s.db.Prepare(updateTracker + ` RETURNING revision`)
 	if err != nil {
 		span.SetStatus(codes.Error, "db error")
-		return err
+		return 0, err
 	}
-	_, err = stmt.ExecContext(ctx,
+
+	var revision models.Revision
+	err = stmt.QueryRowContext(ctx,
 		tracker.Description,
 		tracker.Latitude,
 		tracker.Longitude,
-		tracker.Id())
+		tracker.Id()).Scan(&revision)
 
-	if err != nil {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		span.SetStatus(codes.Error, "db error")
-		return err
+		return 0, err
 	}
 
-	return nil
+	return revision, nil
 }
 
-func (s *Storage) Delete(ctx context.Context, id models.Id) error {
+// Marks the tracker as deleted and returns the revision assigned to the change.
+// The row is kept as a tombstone so incremental listings are able to report the removal.
+// Returns zero revision if there is no such tracker
+func (s *Storage) Delete(ctx context.Context, id models.Id) (models.Revision, error) {
 	const op = "sqlite.Delete"
 
 	ctx, span := s.tracer.Start(ctx, op,
@@ -142,20 +187,61 @@ func (s *Storage) Delete(ctx context.Context, id models.Id) error {
 	)
 	defer span.End()
 
-	stmt, err := s.db.Prepare(`DELETE FROM trackers
-								WHERE id = ?`)
+	stmt, err := s.db.Prepare(deleteTracker + ` RETURNING revision`)
 	if err != nil {
 		span.SetStatus(codes.Error, "db error")
-		return err
+		return 0, err
 	}
 
-	_, err = stmt.ExecContext(ctx, id)
+	var revision models.Revision
+	err = stmt.QueryRowContext(ctx, id).Scan(&revision)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.SetStatus(codes.Error, "db error")
+		return 0, err
+	}
+
+	return revision, nil
+}
+
+// Applies the changes in a single transaction and returns them as events ordered by revision.
+// Updates and deletes of missing trackers are skipped. Nothing is applied if any change fails
+func (s *Storage) Apply(ctx context.Context, changes models.Changeset) ([]models.Event, error) {
+	const op = "sqlite.Apply"
+
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(
+			attribute.Int("inserts", len(changes.Inserts)),
+			attribute.Int("updates", len(changes.Updates)),
+			attribute.Int("deletes", len(changes.Deletes)),
+		),
+	)
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		span.SetStatus(codes.Error, "db error")
-		return err
+		return nil, err
 	}
+	defer tx.Rollback()
 
-	return nil
+	events, err := storage.ApplyChangeset(ctx, tx, applyStatements, changes)
+	if err != nil {
+		if errors.Is(err, storage.ErrTrackerExists) {
+			span.SetStatus(codes.Error, storage.ErrTrackerExists.Error())
+		} else {
+			span.SetStatus(codes.Error, "db error")
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("changes applied", len(events)))
+
+	return events, nil
 }
 
 func (s *Storage) Trackers(ctx context.Context) ([]models.Tracker, error) {
@@ -163,8 +249,9 @@ func (s *Storage) Trackers(ctx context.Context) ([]models.Tracker, error) {
 	ctx, span := s.tracer.Start(ctx, op)
 	defer span.End()
 
-	stmt, err := s.db.Prepare(`SELECT orig_id, source, description, latitude, longitude
-								FROM trackers`)
+	stmt, err := s.db.Prepare(`SELECT ` + trackerColumns + `
+								FROM trackers
+								WHERE deletedAt IS NULL`)
 	if err != nil {
 		span.SetStatus(codes.Error, "db error")
 		return nil, err
@@ -177,16 +264,10 @@ func (s *Storage) Trackers(ctx context.Context) ([]models.Tracker, error) {
 	}
 	defer rows.Close()
 
-	var res []models.Tracker
-
-	for rows.Next() {
-		tr := models.Tracker{}
-		err := rows.Scan(&tr.OrigId, &tr.Source, &tr.Description, &tr.Latitude, &tr.Longitude)
-		if err != nil {
-			span.SetStatus(codes.Error, "db error")
-			return nil, err
-		}
-		res = append(res, tr)
+	res, err := scanTrackers(rows)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
 	}
 
 	span.SetAttributes(attribute.Int("trackers returned", len(res)))
@@ -194,6 +275,7 @@ func (s *Storage) Trackers(ctx context.Context) ([]models.Tracker, error) {
 	return res, nil
 }
 
+// Returns trackers modified since modifiedFrom including the deleted ones
 func (s *Storage) ModifiedTrackers(ctx context.Context, modifiedFrom time.Time) ([]models.Tracker, error) {
 	const op = "sqlite.ModifiedTrackers"
 	ctx, span := s.tracer.Start(ctx, op)
@@ -204,7 +286,7 @@ func (s *Storage) ModifiedTrackers(ctx context.Context, modifiedFrom time.Time)
 		return nil, errors.New("modifiedFrom argument is zero")
 	}
 
-	stmt, err := s.db.Prepare(`SELECT orig_id, source, description, latitude, longitude
+	stmt, err := s.db.Prepare(`SELECT ` + trackerColumns + `
 								FROM trackers
 								WHERE modifiedAt >= datetime(?, 'unixepoch')`)
 	if err != nil {
@@ -219,16 +301,45 @@ func (s *Storage) ModifiedTrackers(ctx context.Context, modifiedFrom time.Time)
 	}
 	defer rows.Close()
 
-	var res []models.Tracker
+	res, err := scanTrackers(rows)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
 
-	for rows.Next() {
-		tr := models.Tracker{}
-		err := rows.Scan(&tr.OrigId, &tr.Source, &tr.Description, &tr.Latitude, &tr.Longitude)
-		if err != nil {
-			span.SetStatus(codes.Error, "db error")
-			return nil, err
-		}
-		res = append(res, tr)
+	span.SetAttributes(attribute.Int("trackers returned", len(res)))
+
+	return res, nil
+}
+
+// Returns trackers changed after the revision including the deleted ones, ordered by revision
+func (s *Storage) ChangedTrackers(ctx context.Context, after models.Revision) ([]models.Tracker, error) {
+	const op = "sqlite.ChangedTrackers"
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.Int64("after", int64(after))),
+	)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`SELECT ` + trackerColumns + `
+								FROM trackers
+								WHERE revision > ?
+								ORDER BY revision`)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, after)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+	defer rows.Close()
+
+	res, err := scanTrackers(rows)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
 	}
 
 	span.SetAttributes(attribute.Int("trackers returned", len(res)))
@@ -242,7 +353,8 @@ func (s *Storage) Sources(ctx context.Context) ([]string, error) {
 	defer span.End()
 
 	stmt, err := s.db.Prepare(`SELECT DISTINCT source
-								FROM trackers`)
+								FROM trackers
+								WHERE deletedAt IS NULL`)
 	if err != nil {
 		span.SetStatus(codes.Error, "db error")
 		return nil, err
@@ -281,7 +393,7 @@ func (s *Storage) IdsBySource(ctx context.Context, source string) ([]string, err
 
 	stmt, err := s.db.Prepare(`SELECT orig_id
 								FROM trackers
-								WHERE source = ?`)
+								WHERE source = ? AND deletedAt IS NULL`)
 
 	if err != nil {
 		span.SetStatus(codes.Error, "db error")
@@ -315,3 +427,296 @@ func (s *Storage) IdsBySource(ctx context.Context, source string) ([]string, err
 	span.SetAttributes(attribute.Int("Ids returned", len(res)))
 	return res, nil
 }
+
+// Stores measurements in a single transaction.
+// Measurements that have already been stored are skipped
+func (s *Storage) AddMeasurements(ctx context.Context, measurements []models.Measurement) error {
+	const op = "sqlite.AddMeasurements"
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.Int("measurements", len(measurements))),
+	)
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT OR IGNORE INTO
+								measurements(tracker_id, pollutant, value, unit, observedAt)
+								VALUES(?, ?, ?, ?, ?)`)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return err
+	}
+	defer stmt.Close()
+
+	for _, m := range measurements {
+		_, err := stmt.ExecContext(ctx,
+			m.TrackerId,
+			m.Pollutant,
+			m.Value,
+			m.Unit,
+			m.ObservedAt.Unix())
+		if err != nil {
+			span.SetStatus(codes.Error, "db error")
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return err
+	}
+
+	return nil
+}
+
+// Returns every version of the tracker ordered by revision
+func (s *Storage) History(ctx context.Context, id models.Id) ([]models.TrackerVersion, error) {
+	const op = "sqlite.History"
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("trackerId", string(id))),
+	)
+	defer span.End()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT orig_id, source, description, latitude, longitude,
+									revision, kind, validFrom, validTo
+								FROM tracker_history
+								WHERE tracker_id = ?
+								ORDER BY revision`,
+		id)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []models.TrackerVersion
+
+	for rows.Next() {
+		var (
+			v       models.TrackerVersion
+			validTo sql.NullTime
+		)
+		err := rows.Scan(&v.Tracker.OrigId, &v.Tracker.Source, &v.Tracker.Description,
+			&v.Tracker.Latitude, &v.Tracker.Longitude, &v.Tracker.Revision,
+			&v.Kind, &v.ValidFrom, &validTo)
+		if err != nil {
+			span.SetStatus(codes.Error, "db error")
+			return nil, err
+		}
+		v.ValidFrom = v.ValidFrom.UTC()
+		if validTo.Valid {
+			v.ValidTo = validTo.Time.UTC()
+		}
+		if v.Kind == models.VersionRemoved {
+			v.Tracker.DeletedAt = v.ValidFrom
+		}
+		res = append(res, v)
+	}
+	if err := rows.Err(); err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("versions returned", len(res)))
+
+	return res, nil
+}
+
+// Returns measurements of the tracker observed within [from, to) ordered by observation time
+func (s *Storage) Measurements(ctx context.Context, id models.Id, from, to time.Time) ([]models.Measurement, error) {
+	const op = "sqlite.Measurements"
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(attribute.String("trackerId", string(id))),
+	)
+	defer span.End()
+
+	if !from.Before(to) {
+		span.SetStatus(codes.Error, "time range is empty")
+		return nil, errors.New("time range is empty")
+	}
+
+	stmt, err := s.db.Prepare(`SELECT tracker_id, pollutant, value, unit, observedAt
+								FROM measurements
+								WHERE tracker_id = ? AND observedAt >= ? AND observedAt < ?
+								ORDER BY observedAt, pollutant`)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, id, from.Unix(), to.Unix())
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []models.Measurement
+
+	for rows.Next() {
+		var (
+			m          models.Measurement
+			observedAt int64
+		)
+		err := rows.Scan(&m.TrackerId, &m.Pollutant, &m.Value, &m.Unit, &observedAt)
+		if err != nil {
+			span.SetStatus(codes.Error, "db error")
+			return nil, err
+		}
+		m.ObservedAt = time.Unix(observedAt, 0).UTC()
+		res = append(res, m)
+	}
+
+	span.SetAttributes(attribute.Int("measurements returned", len(res)))
+
+	return res, nil
+}
+
+// Returns up to limit trackers within radius meters of the point ordered by distance.
+// Candidates are selected by the bounding rectangle of the circle, then filtered by exact distance
+func (s *Storage) Nearest(ctx context.Context, point geo.Point, radius float64, limit int) ([]models.NearTracker, error) {
+	const op = "sqlite.Nearest"
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(
+			attribute.Float64("latitude", point.Latitude),
+			attribute.Float64("longitude", point.Longitude),
+			attribute.Float64("radius", radius),
+		),
+	)
+	defer span.End()
+
+	candidates, err := s.trackersInBounds(ctx, geo.BoundsAround(point, radius), nil)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+
+	var res []models.NearTracker
+
+	for _, tr := range candidates {
+		distance := geo.Distance(point, geo.Point{Latitude: tr.Latitude, Longitude: tr.Longitude})
+		if distance <= radius {
+			res = append(res, models.NearTracker{Tracker: tr, Distance: distance})
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Distance < res[j].Distance
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	span.SetAttributes(attribute.Int("trackers returned", len(res)))
+
+	return res, nil
+}
+
+// Returns trackers inside the rectangle, of the given sources only if there are any
+func (s *Storage) TrackersInBounds(ctx context.Context, bounds geo.Bounds, sources []string) ([]models.Tracker, error) {
+	const op = "sqlite.TrackersInBounds"
+	ctx, span := s.tracer.Start(ctx, op,
+		trace.WithAttributes(
+			attribute.Float64("south", bounds.South),
+			attribute.Float64("west", bounds.West),
+			attribute.Float64("north", bounds.North),
+			attribute.Float64("east", bounds.East),
+			attribute.StringSlice("sources", sources),
+		),
+	)
+	defer span.End()
+
+	res, err := s.trackersInBounds(ctx, bounds, sources)
+	if err != nil {
+		span.SetStatus(codes.Error, "db error")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("trackers returned", len(res)))
+
+	return res, nil
+}
+
+// Candidates are looked up in the trackers_rtree index. The index stores
+// 32-bit rounded coordinates, so the exact coordinates are checked as well
+func (s *Storage) trackersInBounds(ctx context.Context, bounds geo.Bounds, sources []string) ([]models.Tracker, error) {
+	const rtreeCond = `SELECT id FROM trackers_rtree
+						WHERE maxLat >= ? AND minLat <= ? AND maxLng >= ? AND minLng <= ?`
+
+	lngCond := `longitude BETWEEN ? AND ?`
+	rtreeQuery := rtreeCond
+	args := []any{bounds.South, bounds.North, bounds.West, bounds.East}
+
+	// the rtree module can't use OR, the rectangle is split in two at the antimeridian
+	if bounds.CrossesAntimeridian() {
+		lngCond = `(longitude >= ? OR longitude <= ?)`
+		rtreeQuery = rtreeCond + ` UNION ALL ` + rtreeCond
+		args = []any{
+			bounds.South, bounds.North, bounds.West, 180.0,
+			bounds.South, bounds.North, -180.0, bounds.East,
+		}
+	}
+
+	args = append(args, bounds.South, bounds.North, bounds.West, bounds.East)
+
+	var sourceCond string
+	if len(sources) != 0 {
+		sourceCond = ` AND source IN (?` + strings.Repeat(`, ?`, len(sources)-1) + `)`
+		for _, source := range sources {
+			args = append(args, source)
+		}
+	}
+
+	stmt, err := s.db.Prepare(`SELECT ` + trackerColumns + `
+								FROM trackers
+								WHERE rowid IN (` + rtreeQuery + `)
+									AND deletedAt IS NULL
+									AND latitude BETWEEN ? AND ?
+									AND ` + lngCond + sourceCond)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTrackers(rows)
+}
+
+func scanTrackers(rows *sql.Rows) ([]models.Tracker, error) {
+	var res []models.Tracker
+
+	for rows.Next() {
+		tr, err := scanTracker(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, tr)
+	}
+
+	return res, rows.Err()
+}
+
+// Scans trackerColumns of a single row
+func scanTracker(row interface{ Scan(dest ...any) error }) (models.Tracker, error) {
+	var (
+		tr        models.Tracker
+		deletedAt sql.NullTime
+	)
+	err := row.Scan(&tr.OrigId, &tr.Source, &tr.Description, &tr.Latitude, &tr.Longitude,
+		&deletedAt, &tr.Revision)
+	if err != nil {
+		return models.Tracker{}, err
+	}
+	tr.DeletedAt = deletedAt.Time
+
+	return tr, nil
+}
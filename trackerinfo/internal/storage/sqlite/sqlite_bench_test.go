@@ -0,0 +1,105 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/geo"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage/sqlite"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+var benchSizes = []int{1_000, 10_000, 100_000, 300_000}
+
+// Query time is expected to stay flat as the table grows
+func BenchmarkNearest(b *testing.B) {
+	ctx := context.Background()
+	point := geo.Point{Latitude: 40.182, Longitude: 44.516}
+
+	for _, size := range benchSizes {
+		storage := newBenchStorage(b, size)
+
+		b.Run(fmt.Sprintf("trackers=%d", size), func(b *testing.B) {
+			for range b.N {
+				_, err := storage.Nearest(ctx, point, 50_000, 10)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+func BenchmarkTrackersInBounds(b *testing.B) {
+	ctx := context.Background()
+	viewport := geo.Bounds{South: 39, West: 43, North: 41, East: 46}
+	antimeridian := geo.Bounds{South: -1, West: 179, North: 1, East: -179}
+
+	for _, size := range benchSizes {
+		storage := newBenchStorage(b, size)
+
+		b.Run(fmt.Sprintf("viewport/trackers=%d", size), func(b *testing.B) {
+			for range b.N {
+				_, err := storage.TrackersInBounds(ctx, viewport, nil)
+				require.NoError(b, err)
+			}
+		})
+
+		b.Run(fmt.Sprintf("antimeridian/trackers=%d", size), func(b *testing.B) {
+			for range b.N {
+				_, err := storage.TrackersInBounds(ctx, antimeridian, nil)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+// Creates a migrated memory storage with trackers spread uniformly over the globe
+func newBenchStorage(b *testing.B, size int) *sqlite.Storage {
+	b.Helper()
+	const migrationPath = "../../../migrations"
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(b, err)
+	// every connection to :memory: opens a separate database
+	db.SetMaxOpenConns(1)
+	b.Cleanup(func() { db.Close() })
+
+	driver, err := sqlite3.WithInstance(db, &sqlite3.Config{})
+	require.NoError(b, err)
+
+	m, err := migrate.NewWithDatabaseInstance("file://"+migrationPath, "sqlite3", driver)
+	require.NoError(b, err)
+	require.NoError(b, m.Up())
+
+	tx, err := db.Begin()
+	require.NoError(b, err)
+
+	stmt, err := tx.Prepare(`INSERT INTO
+							trackers(id, orig_id, source, description, latitude, longitude, revision)
+							VALUES(?, ?, ?, ?, ?, ?, ?)`)
+	require.NoError(b, err)
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := range size {
+		origId := fmt.Sprint(i)
+		// uniform on the sphere
+		lat := math.Asin(2*rnd.Float64()-1) * 180 / math.Pi
+		lng := rnd.Float64()*360 - 180
+
+		_, err := stmt.Exec("bench|"+origId, origId, "bench", "station "+origId, lat, lng, i+1)
+		require.NoError(b, err)
+	}
+	require.NoError(b, tx.Commit())
+
+	storage, err := sqlite.New(otel.Tracer(""), sqlite.WithDatabaseInstance(db))
+	require.NoError(b, err)
+
+	return storage
+}
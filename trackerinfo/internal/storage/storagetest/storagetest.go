@@ -0,0 +1,618 @@
+// Package storagetest holds the contract every trackerlist.Storage implementation has to satisfy.
+// A backend test calls Run with a factory returning an empty migrated storage
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/geo"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/models"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/services/trackerlist"
+	"github.com/MRibalko/smogtracker/trackerinfo/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// Storage under test. TrackerList writes with Apply only,
+// the single-tracker writes of the backends are used to set up the data
+type Storage interface {
+	trackerlist.Storage
+	Insert(ctx context.Context, tracker models.Tracker) (models.Revision, error)
+	Update(ctx context.Context, tracker models.Tracker) (models.Revision, error)
+	Delete(ctx context.Context, id models.Id) (models.Revision, error)
+}
+
+// Returns an empty storage. It is called once per subtest
+type Factory func(t *testing.T) Storage
+
+var testTracker = models.Tracker{
+	OrigId:      "1",
+	Source:      "test1",
+	Description: "some description",
+	Latitude:    1.3224,
+	Longitude:   5.221,
+}
+
+// Runs the whole suite against storages made by newStorage
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, s Storage)
+	}{
+		{"Insert", testInsert},
+		{"Update", testUpdate},
+		{"Delete", testDelete},
+		{"Apply", testApply},
+		{"Apply rolls back", testApplyRollback},
+		{"ModifiedTrackers", testModifiedTrackers},
+		{"ChangedTrackers", testChangedTrackers},
+		{"Sources", testSources},
+		{"IdsBySource", testIdsBySource},
+		{"Measurements", testMeasurements},
+		{"Nearest", testNearest},
+		{"TrackersInBounds", testTrackersInBounds},
+		{"Location follows changes", testLocationFollowsChanges},
+		{"History", testHistory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStorage(t))
+		})
+	}
+}
+
+func testInsert(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	revision, err := s.Insert(ctx, testTracker)
+	require.NoError(t, err)
+	require.Positive(t, revision)
+
+	_, err = s.Insert(ctx, testTracker)
+	require.ErrorIs(t, err, storage.ErrTrackerExists)
+
+	res, err := s.Trackers(ctx)
+	require.NoError(t, err)
+
+	want := testTracker
+	want.Revision = revision
+	require.Equal(t, []models.Tracker{want}, res)
+}
+
+func testUpdate(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	revision, err := s.Insert(ctx, testTracker)
+	require.NoError(t, err)
+
+	updTracker := testTracker
+	updTracker.Description = "new"
+	updTracker.Latitude = 2.1
+	updTracker.Longitude = 1.1
+
+	updTracker.Revision, err = s.Update(ctx, updTracker)
+	require.NoError(t, err)
+	require.Greater(t, updTracker.Revision, revision)
+
+	res, err := s.Trackers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []models.Tracker{updTracker}, res)
+
+	// there is nothing to update
+	missing := testTracker
+	missing.OrigId = "missing"
+	revision, err = s.Update(ctx, missing)
+	require.NoError(t, err)
+	require.Zero(t, revision)
+}
+
+func testDelete(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	_, err := s.Insert(ctx, testTracker)
+	require.NoError(t, err)
+
+	revision, err := s.Delete(ctx, testTracker.Id())
+	require.NoError(t, err)
+	require.Positive(t, revision)
+
+	res, err := s.Trackers(ctx)
+	require.NoError(t, err)
+	require.Empty(t, res)
+
+	revision, err = s.Delete(ctx, testTracker.Id())
+	require.NoError(t, err)
+	require.Zero(t, revision)
+
+	// deleted tracker can't be updated
+	upd := testTracker
+	upd.Description = "new"
+	revision, err = s.Update(ctx, upd)
+	require.NoError(t, err)
+	require.Zero(t, revision)
+
+	// inserting the deleted tracker brings it back
+	upd.Revision, err = s.Insert(ctx, upd)
+	require.NoError(t, err)
+
+	_, err = s.Insert(ctx, upd)
+	require.ErrorIs(t, err, storage.ErrTrackerExists)
+
+	res, err = s.Trackers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []models.Tracker{upd}, res)
+}
+
+func testApply(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	updated := testTracker
+	updated.OrigId = "updated"
+	deleted := testTracker
+	deleted.OrigId = "deleted"
+	for _, tr := range []models.Tracker{updated, deleted} {
+		_, err := s.Insert(ctx, tr)
+		require.NoError(t, err)
+	}
+
+	inserted := testTracker
+	updated.Description = "new"
+	missing := testTracker
+	missing.OrigId = "missing"
+
+	events, err := s.Apply(ctx, models.Changeset{
+		Inserts: []models.Tracker{inserted},
+		Updates: []models.Tracker{updated, missing},
+		Deletes: []models.Id{deleted.Id(), missing.Id()},
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 3, "changes of missing trackers are skipped")
+	for i := 1; i < len(events); i++ {
+		require.Greater(t, events[i].Tracker.Revision, events[i-1].Tracker.Revision)
+	}
+
+	inserted.Revision = events[0].Tracker.Revision
+	updated.Revision = events[1].Tracker.Revision
+	require.Equal(t, models.Event{Type: models.ChangeInserted, Tracker: inserted}, events[0])
+	require.Equal(t, models.Event{Type: models.ChangeUpdated, Tracker: updated}, events[1])
+	require.Equal(t, models.ChangeDeleted, events[2].Type)
+	require.Equal(t, deleted.Id(), events[2].Tracker.Id())
+	require.True(t, events[2].Tracker.IsDeleted())
+
+	res, err := s.Trackers(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []models.Tracker{inserted, updated}, res)
+
+	events, err = s.Apply(ctx, models.Changeset{})
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+// A failed change leaves the storage as it was
+func testApplyRollback(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	var err error
+	existing := testTracker
+	existing.Revision, err = s.Insert(ctx, existing)
+	require.NoError(t, err)
+
+	fresh := testTracker
+	fresh.OrigId = "fresh"
+	updated := existing
+	updated.Description = "new"
+
+	_, err = s.Apply(ctx, models.Changeset{
+		Inserts: []models.Tracker{fresh, existing},
+		Updates: []models.Tracker{updated},
+		Deletes: []models.Id{existing.Id()},
+	})
+	require.ErrorIs(t, err, storage.ErrTrackerExists)
+
+	res, err := s.Trackers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []models.Tracker{existing}, res)
+
+	changed, err := s.ChangedTrackers(ctx, existing.Revision)
+	require.NoError(t, err)
+	require.Empty(t, changed)
+}
+
+// Storages keep modification time with a second resolution
+func testModifiedTrackers(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	_, err := s.ModifiedTrackers(ctx, time.Time{})
+	require.Error(t, err)
+
+	_, err = s.Insert(ctx, testTracker)
+	require.NoError(t, err)
+
+	updated := testTracker
+	updated.OrigId = "2"
+	_, err = s.Insert(ctx, updated)
+	require.NoError(t, err)
+
+	deleted := testTracker
+	deleted.OrigId = "3"
+	_, err = s.Insert(ctx, deleted)
+	require.NoError(t, err)
+
+	time.Sleep(1 * time.Second)
+	now := time.Now()
+
+	updated.Description = "new description"
+	updated.Revision, err = s.Update(ctx, updated)
+	require.NoError(t, err)
+
+	_, err = s.Delete(ctx, deleted.Id())
+	require.NoError(t, err)
+
+	res, err := s.ModifiedTrackers(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	for _, tr := range res {
+		switch tr.Id() {
+		case updated.Id():
+			require.Equal(t, updated, tr)
+		case deleted.Id():
+			require.True(t, tr.IsDeleted())
+		default:
+			require.Failf(t, "unexpected tracker", "%s is not modified", tr.Id())
+		}
+	}
+
+	res, err = s.ModifiedTrackers(ctx, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Empty(t, res)
+}
+
+func testChangedTrackers(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	for _, id := range []string{"1", "2", "3"} {
+		tr := testTracker
+		tr.OrigId = id
+		_, err := s.Insert(ctx, tr)
+		require.NoError(t, err)
+	}
+
+	all, err := s.ChangedTrackers(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	for i := 1; i < len(all); i++ {
+		require.Greater(t, all[i].Revision, all[i-1].Revision)
+	}
+	last := all[len(all)-1].Revision
+
+	updated := all[0]
+	updated.Description = "updated"
+	updated.Revision, err = s.Update(ctx, updated)
+	require.NoError(t, err)
+	require.Greater(t, updated.Revision, last)
+
+	deletedRevision, err := s.Delete(ctx, all[1].Id())
+	require.NoError(t, err)
+	require.Greater(t, deletedRevision, updated.Revision)
+
+	res, err := s.ChangedTrackers(ctx, last)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	require.Equal(t, updated, res[0])
+	require.True(t, res[1].IsDeleted())
+	require.Equal(t, deletedRevision, res[1].Revision)
+
+	res, err = s.ChangedTrackers(ctx, deletedRevision)
+	require.NoError(t, err)
+	require.Empty(t, res)
+}
+
+func testSources(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	res, err := s.Sources(ctx)
+	require.NoError(t, err)
+	require.Empty(t, res)
+
+	sources := []string{"test1", "test2", "test3"}
+	for _, source := range sources {
+		_, err := s.Insert(ctx, models.Tracker{
+			OrigId: "1",
+			Source: source,
+		})
+		require.NoError(t, err)
+	}
+
+	_, err = s.Insert(ctx, models.Tracker{
+		OrigId: "2",
+		Source: sources[0],
+	})
+	require.NoError(t, err)
+
+	res, err = s.Sources(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, sources, res)
+
+	// a source with deleted trackers only is gone
+	_, err = s.Delete(ctx, trackerId(sources[2], "1"))
+	require.NoError(t, err)
+
+	res, err = s.Sources(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, sources[:2], res)
+}
+
+func testIdsBySource(t *testing.T, s Storage) {
+	ctx := context.Background()
+	const (
+		source            = "source1"
+		notExistingSource = "nosource"
+	)
+
+	ids := []string{"id1", "id2", "id3"}
+	for _, id := range ids {
+		_, err := s.Insert(ctx, models.Tracker{
+			OrigId: id,
+			Source: source,
+		})
+		require.NoError(t, err)
+	}
+
+	res, err := s.IdsBySource(ctx, notExistingSource)
+	require.ErrorIs(t, err, storage.ErrSourceNotFound)
+	require.Empty(t, res)
+
+	res, err = s.IdsBySource(ctx, source)
+	require.NoError(t, err)
+	require.ElementsMatch(t, ids, res)
+
+	_, err = s.Delete(ctx, trackerId(source, ids[0]))
+	require.NoError(t, err)
+
+	res, err = s.IdsBySource(ctx, source)
+	require.NoError(t, err)
+	require.ElementsMatch(t, ids[1:], res)
+
+	for _, id := range ids[1:] {
+		_, err = s.Delete(ctx, trackerId(source, id))
+		require.NoError(t, err)
+	}
+
+	_, err = s.IdsBySource(ctx, source)
+	require.ErrorIs(t, err, storage.ErrSourceNotFound)
+}
+
+func testMeasurements(t *testing.T, s Storage) {
+	ctx := context.Background()
+	start := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	var measurements []models.Measurement
+	for i := range 3 {
+		measurements = append(measurements,
+			models.Measurement{
+				TrackerId:  testTracker.Id(),
+				Pollutant:  models.PM25,
+				Value:      float64(i),
+				Unit:       models.UnitMicrogramsPerCubicMeter,
+				ObservedAt: start.Add(time.Duration(i) * time.Hour),
+			},
+			models.Measurement{
+				TrackerId:  testTracker.Id(),
+				Pollutant:  models.PM10,
+				Value:      float64(i) * 2,
+				Unit:       models.UnitMicrogramsPerCubicMeter,
+				ObservedAt: start.Add(time.Duration(i) * time.Hour),
+			})
+	}
+	// a reading of another tracker is not returned
+	err := s.AddMeasurements(ctx, append(measurements, models.Measurement{
+		TrackerId:  "test1|other",
+		Pollutant:  models.PM25,
+		Value:      100,
+		Unit:       models.UnitMicrogramsPerCubicMeter,
+		ObservedAt: start,
+	}))
+	require.NoError(t, err)
+
+	// the same readings fetched twice are stored once
+	err = s.AddMeasurements(ctx, measurements)
+	require.NoError(t, err)
+
+	res, err := s.Measurements(ctx, testTracker.Id(), start, start.Add(3*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, res, len(measurements))
+	for i := 1; i < len(res); i++ {
+		require.False(t, res[i].ObservedAt.Before(res[i-1].ObservedAt))
+	}
+	require.ElementsMatch(t, measurements, res)
+
+	res, err = s.Measurements(ctx, testTracker.Id(), start, start.Add(time.Hour))
+	require.NoError(t, err)
+	require.ElementsMatch(t, measurements[:2], res)
+
+	res, err = s.Measurements(ctx, testTracker.Id(), start.Add(-time.Hour), start)
+	require.NoError(t, err)
+	require.Empty(t, res)
+
+	_, err = s.Measurements(ctx, testTracker.Id(), start, start)
+	require.Error(t, err)
+}
+
+func testNearest(t *testing.T, s Storage) {
+	ctx := context.Background()
+	var err error
+
+	trackers := []models.Tracker{
+		{OrigId: "east", Source: "geo", Longitude: -179.95},
+		{OrigId: "west", Source: "geo", Longitude: 179.9},
+		{OrigId: "far", Source: "geo", Longitude: 170},
+		{OrigId: "deleted", Source: "geo", Longitude: 179.95},
+	}
+	for i, tr := range trackers {
+		trackers[i].Revision, err = s.Insert(ctx, tr)
+		require.NoError(t, err)
+	}
+	_, err = s.Delete(ctx, trackers[3].Id())
+	require.NoError(t, err)
+
+	point := geo.Point{Latitude: 0, Longitude: 179.95}
+
+	res, err := s.Nearest(ctx, point, 20000, 10)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	require.Equal(t, trackers[1], res[0].Tracker)
+	require.InDelta(t, 5560, res[0].Distance, 1)
+	require.Equal(t, trackers[0], res[1].Tracker)
+	require.InDelta(t, 11119, res[1].Distance, 1)
+
+	res, err = s.Nearest(ctx, point, 20000, 1)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, trackers[1], res[0].Tracker)
+
+	res, err = s.Nearest(ctx, point, 1000, 10)
+	require.NoError(t, err)
+	require.Empty(t, res)
+}
+
+func testTrackersInBounds(t *testing.T, s Storage) {
+	ctx := context.Background()
+	var err error
+
+	trackers := []models.Tracker{
+		{OrigId: "1", Source: "bounds1", Latitude: 10, Longitude: 179},
+		{OrigId: "2", Source: "bounds1", Latitude: 10, Longitude: -179},
+		{OrigId: "3", Source: "bounds2", Latitude: 10, Longitude: 179.5},
+		{OrigId: "4", Source: "bounds1", Latitude: 10, Longitude: 0},
+	}
+	for i, tr := range trackers {
+		trackers[i].Revision, err = s.Insert(ctx, tr)
+		require.NoError(t, err)
+	}
+
+	res, err := s.TrackersInBounds(ctx, geo.Bounds{South: 5, West: 170, North: 15, East: -170}, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, trackers[:3], res)
+
+	res, err = s.TrackersInBounds(ctx, geo.Bounds{South: 5, West: 170, North: 15, East: -170}, []string{"bounds2"})
+	require.NoError(t, err)
+	require.Equal(t, trackers[2:3], res)
+
+	res, err = s.TrackersInBounds(ctx, geo.Bounds{South: 5, West: -10, North: 15, East: 10}, []string{"bounds1", "bounds2"})
+	require.NoError(t, err)
+	require.Equal(t, trackers[3:], res)
+
+	res, err = s.TrackersInBounds(ctx, geo.Bounds{South: 11, West: -180, North: 15, East: 180}, nil)
+	require.NoError(t, err)
+	require.Empty(t, res)
+}
+
+// Spatial lookups see moved, deleted and restored trackers
+func testLocationFollowsChanges(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	tr := models.Tracker{OrigId: "moving", Source: "index", Latitude: -40, Longitude: 100}
+	oldPlace := geo.Bounds{South: -41, West: 99, North: -39, East: 101}
+	newPlace := geo.Bounds{South: -46, West: 104, North: -44, East: 106}
+
+	_, err := s.Insert(ctx, tr)
+	require.NoError(t, err)
+
+	res, err := s.TrackersInBounds(ctx, oldPlace, nil)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+
+	tr.Latitude, tr.Longitude = -45, 105
+	tr.Revision, err = s.Update(ctx, tr)
+	require.NoError(t, err)
+
+	res, err = s.TrackersInBounds(ctx, oldPlace, nil)
+	require.NoError(t, err)
+	require.Empty(t, res)
+
+	res, err = s.TrackersInBounds(ctx, newPlace, nil)
+	require.NoError(t, err)
+	require.Equal(t, []models.Tracker{tr}, res)
+
+	_, err = s.Delete(ctx, tr.Id())
+	require.NoError(t, err)
+
+	res, err = s.TrackersInBounds(ctx, newPlace, nil)
+	require.NoError(t, err)
+	require.Empty(t, res)
+
+	tr.Revision, err = s.Insert(ctx, tr)
+	require.NoError(t, err)
+
+	res, err = s.TrackersInBounds(ctx, newPlace, nil)
+	require.NoError(t, err)
+	require.Equal(t, []models.Tracker{tr}, res)
+}
+
+func testHistory(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	res, err := s.History(ctx, testTracker.Id())
+	require.NoError(t, err)
+	require.Empty(t, res)
+
+	tr := testTracker
+	_, err = s.Insert(ctx, tr)
+	require.NoError(t, err)
+
+	tr.Description = "renamed"
+	_, err = s.Update(ctx, tr)
+	require.NoError(t, err)
+
+	tr.Latitude, tr.Longitude = 40.182, 44.516
+	_, err = s.Update(ctx, tr)
+	require.NoError(t, err)
+
+	_, err = s.Delete(ctx, tr.Id())
+	require.NoError(t, err)
+
+	_, err = s.Insert(ctx, tr)
+	require.NoError(t, err)
+
+	// another tracker is not in the history
+	other := testTracker
+	other.OrigId = "other"
+	_, err = s.Insert(ctx, other)
+	require.NoError(t, err)
+
+	res, err = s.History(ctx, tr.Id())
+	require.NoError(t, err)
+	require.Len(t, res, 5)
+
+	kinds := []models.VersionKind{
+		models.VersionCreated,
+		models.VersionRenamed,
+		models.VersionMoved,
+		models.VersionRemoved,
+		models.VersionCreated,
+	}
+	for i, v := range res {
+		require.Equal(t, kinds[i], v.Kind)
+		require.Equal(t, tr.Id(), v.Tracker.Id())
+		require.False(t, v.ValidFrom.IsZero())
+		if i > 0 {
+			require.Greater(t, v.Tracker.Revision, res[i-1].Tracker.Revision)
+			require.Equal(t, v.ValidFrom, res[i-1].ValidTo, "versions follow each other")
+		}
+	}
+	require.True(t, res[4].ValidTo.IsZero(), "the last version is current")
+
+	require.Equal(t, "some description", res[0].Tracker.Description)
+	require.Equal(t, "renamed", res[1].Tracker.Description)
+	require.Equal(t, testTracker.Latitude, res[1].Tracker.Latitude)
+	require.Equal(t, 40.182, res[2].Tracker.Latitude)
+	require.True(t, res[3].Tracker.IsDeleted())
+	require.False(t, res[4].Tracker.IsDeleted())
+}
+
+func trackerId(source, origId string) models.Id {
+	tr := models.Tracker{OrigId: origId, Source: source}
+	return tr.Id()
+}
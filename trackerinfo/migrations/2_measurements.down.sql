@@ -0,0 +1 @@
+DROP TABLE measurements
\ No newline at end of file
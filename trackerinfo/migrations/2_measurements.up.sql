@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS measurements
+(
+    tracker_id      TEXT NOT NULL,
+    pollutant       TEXT NOT NULL,
+    value           REAL NOT NULL,
+    unit            TEXT NOT NULL,
+    observedAt      INTEGER NOT NULL,
+    PRIMARY KEY (tracker_id, pollutant, observedAt)
+);
\ No newline at end of file
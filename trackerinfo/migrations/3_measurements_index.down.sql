@@ -0,0 +1 @@
+DROP INDEX measurements_tracker_observed
\ No newline at end of file
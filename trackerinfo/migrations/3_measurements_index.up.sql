@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS measurements_tracker_observed
+    ON measurements (tracker_id, observedAt);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE trackers DROP COLUMN deletedAt
\ No newline at end of file
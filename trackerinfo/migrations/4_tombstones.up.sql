@@ -0,0 +1 @@
+ALTER TABLE trackers ADD COLUMN deletedAt DATETIME;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX trackers_revision;
+
+ALTER TABLE trackers DROP COLUMN revision;
\ No newline at end of file
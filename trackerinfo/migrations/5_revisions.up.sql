@@ -0,0 +1,6 @@
+ALTER TABLE trackers ADD COLUMN revision INTEGER NOT NULL DEFAULT 0;
+
+UPDATE trackers SET revision = rowid;
+
+CREATE UNIQUE INDEX IF NOT EXISTS trackers_revision
+    ON trackers (revision);
\ No newline at end of file
@@ -0,0 +1,4 @@
+DROP TRIGGER trackers_rtree_delete;
+DROP TRIGGER trackers_rtree_update;
+DROP TRIGGER trackers_rtree_insert;
+DROP TABLE trackers_rtree;
\ No newline at end of file
@@ -0,0 +1,39 @@
+CREATE VIRTUAL TABLE IF NOT EXISTS trackers_rtree USING rtree
+(
+    id,
+    minLat, maxLat,
+    minLng, maxLng
+);
+
+INSERT INTO trackers_rtree(id, minLat, maxLat, minLng, maxLng)
+    SELECT rowid, latitude, latitude, longitude, longitude
+    FROM trackers
+    WHERE deletedAt IS NULL;
+
+CREATE TRIGGER [trackers_rtree_insert]
+    AFTER INSERT
+    ON trackers
+    WHEN new.deletedAt IS NULL
+BEGIN
+    INSERT INTO trackers_rtree(id, minLat, maxLat, minLng, maxLng)
+        VALUES (new.rowid, new.latitude, new.latitude, new.longitude, new.longitude);
+END;
+
+CREATE TRIGGER [trackers_rtree_update]
+    AFTER UPDATE OF latitude, longitude, deletedAt
+    ON trackers
+FOR EACH ROW
+BEGIN
+    DELETE FROM trackers_rtree WHERE id = old.rowid;
+    INSERT INTO trackers_rtree(id, minLat, maxLat, minLng, maxLng)
+        SELECT new.rowid, new.latitude, new.latitude, new.longitude, new.longitude
+        WHERE new.deletedAt IS NULL;
+END;
+
+CREATE TRIGGER [trackers_rtree_delete]
+    AFTER DELETE
+    ON trackers
+FOR EACH ROW
+BEGIN
+    DELETE FROM trackers_rtree WHERE id = old.rowid;
+END;
\ No newline at end of file
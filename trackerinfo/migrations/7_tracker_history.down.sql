@@ -0,0 +1,3 @@
+DROP TRIGGER tracker_history_update;
+DROP TRIGGER tracker_history_insert;
+DROP TABLE tracker_history;
\ No newline at end of file
@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS tracker_history
+(
+    tracker_id      TEXT NOT NULL,
+    revision        INTEGER NOT NULL,
+    kind            TEXT NOT NULL,
+    orig_id         TEXT NOT NULL,
+    source          TEXT NOT NULL,
+    description     TEXT NOT NULL,
+    latitude        REAL,
+    longitude       REAL,
+    validFrom       DATETIME NOT NULL,
+    validTo         DATETIME,
+    PRIMARY KEY (tracker_id, revision)
+);
+
+INSERT INTO tracker_history(tracker_id, revision, kind, orig_id, source, description,
+                            latitude, longitude, validFrom)
+    SELECT id, revision, CASE WHEN deletedAt IS NULL THEN 'created' ELSE 'removed' END,
+           orig_id, source, description, latitude, longitude, COALESCE(deletedAt, modifiedAt)
+    FROM trackers;
+
+CREATE TRIGGER [tracker_history_insert]
+    AFTER INSERT
+    ON trackers
+FOR EACH ROW
+BEGIN
+    INSERT INTO tracker_history(tracker_id, revision, kind, orig_id, source, description,
+                                latitude, longitude, validFrom)
+        VALUES (new.id, new.revision, 'created', new.orig_id, new.source, new.description,
+                new.latitude, new.longitude, CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER [tracker_history_update]
+    AFTER UPDATE OF revision
+    ON trackers
+FOR EACH ROW
+BEGIN
+    UPDATE tracker_history SET validTo = CURRENT_TIMESTAMP
+        WHERE tracker_id = new.id AND validTo IS NULL;
+    INSERT INTO tracker_history(tracker_id, revision, kind, orig_id, source, description,
+                                latitude, longitude, validFrom)
+        VALUES (new.id, new.revision,
+                CASE
+                    WHEN new.deletedAt IS NOT NULL THEN 'removed'
+                    WHEN old.deletedAt IS NOT NULL THEN 'created'
+                    WHEN new.latitude IS NOT old.latitude OR new.longitude IS NOT old.longitude THEN 'moved'
+                    ELSE 'renamed'
+                END,
+                new.orig_id, new.source, new.description,
+                new.latitude, new.longitude, CURRENT_TIMESTAMP);
+END;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE measurements;
+DROP TABLE trackers;
+DROP SEQUENCE trackers_revision_seq;
\ No newline at end of file
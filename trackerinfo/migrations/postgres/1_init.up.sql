@@ -0,0 +1,37 @@
+CREATE SEQUENCE IF NOT EXISTS trackers_revision_seq;
+
+CREATE TABLE IF NOT EXISTS trackers
+(
+    id              TEXT PRIMARY KEY,
+    orig_id         TEXT NOT NULL,
+    source          TEXT NOT NULL,
+    description     TEXT NOT NULL,
+    latitude        DOUBLE PRECISION,
+    longitude       DOUBLE PRECISION,
+    modifiedAt      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    deletedAt       TIMESTAMPTZ,
+    revision        BIGINT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS trackers_revision
+    ON trackers (revision);
+
+CREATE INDEX IF NOT EXISTS trackers_source
+    ON trackers (source);
+
+CREATE INDEX IF NOT EXISTS trackers_location
+    ON trackers (latitude, longitude)
+    WHERE deletedAt IS NULL;
+
+CREATE TABLE IF NOT EXISTS measurements
+(
+    tracker_id      TEXT NOT NULL,
+    pollutant       TEXT NOT NULL,
+    value           DOUBLE PRECISION NOT NULL,
+    unit            TEXT NOT NULL,
+    observedAt      BIGINT NOT NULL,
+    PRIMARY KEY (tracker_id, pollutant, observedAt)
+);
+
+CREATE INDEX IF NOT EXISTS measurements_tracker_observed
+    ON measurements (tracker_id, observedAt);
\ No newline at end of file
@@ -0,0 +1,4 @@
+DROP TRIGGER tracker_history_update ON trackers;
+DROP TRIGGER tracker_history_insert ON trackers;
+DROP FUNCTION tracker_history_record();
+DROP TABLE tracker_history;
\ No newline at end of file
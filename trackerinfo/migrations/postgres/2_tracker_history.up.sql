@@ -0,0 +1,59 @@
+CREATE TABLE IF NOT EXISTS tracker_history
+(
+    tracker_id      TEXT NOT NULL,
+    revision        BIGINT NOT NULL,
+    kind            TEXT NOT NULL,
+    orig_id         TEXT NOT NULL,
+    source          TEXT NOT NULL,
+    description     TEXT NOT NULL,
+    latitude        DOUBLE PRECISION,
+    longitude       DOUBLE PRECISION,
+    validFrom       TIMESTAMPTZ NOT NULL,
+    validTo         TIMESTAMPTZ,
+    PRIMARY KEY (tracker_id, revision)
+);
+
+INSERT INTO tracker_history(tracker_id, revision, kind, orig_id, source, description,
+                            latitude, longitude, validFrom)
+    SELECT id, revision, CASE WHEN deletedAt IS NULL THEN 'created' ELSE 'removed' END,
+           orig_id, source, description, latitude, longitude, COALESCE(deletedAt, modifiedAt)
+    FROM trackers;
+
+CREATE OR REPLACE FUNCTION tracker_history_record() RETURNS trigger AS $$
+DECLARE
+    change TEXT := 'created';
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        IF new.deletedAt IS NOT NULL THEN
+            change := 'removed';
+        ELSIF old.deletedAt IS NULL THEN
+            IF new.latitude IS DISTINCT FROM old.latitude OR new.longitude IS DISTINCT FROM old.longitude THEN
+                change := 'moved';
+            ELSE
+                change := 'renamed';
+            END IF;
+        END IF;
+
+        UPDATE tracker_history SET validTo = now()
+            WHERE tracker_id = new.id AND validTo IS NULL;
+    END IF;
+
+    INSERT INTO tracker_history(tracker_id, revision, kind, orig_id, source, description,
+                                latitude, longitude, validFrom)
+        VALUES (new.id, new.revision, change, new.orig_id, new.source, new.description,
+                new.latitude, new.longitude, now());
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tracker_history_insert
+    AFTER INSERT
+    ON trackers
+    FOR EACH ROW
+    EXECUTE FUNCTION tracker_history_record();
+
+CREATE TRIGGER tracker_history_update
+    AFTER UPDATE OF revision
+    ON trackers
+    FOR EACH ROW
+    EXECUTE FUNCTION tracker_history_record();
\ No newline at end of file